@@ -0,0 +1,113 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// OriginOverride is the origin of a variable whose value was given with Set.
+const OriginOverride = "override"
+
+// Child returns an EnvSet overlaying e, e.g. to hold the per-request overrides
+// of a multi-tenant service. Lookup, Get, the typed getters and the printers of
+// the child see its own variables first and fall back to those of e. Set copies
+// a variable of e into the child before changing it, so e is never modified.
+// The child shares the name mapper, source, logger and bool words of e.
+func (e *EnvSet) Child() *EnvSet {
+	e.Lock()
+	defer e.Unlock()
+	child := NewEnvSet(e.name)
+	child.parent = e
+	child.mapper = e.mapper
+	child.source = e.source
+	child.logger = e.logger
+	child.boolWords = e.boolWords
+	child.duplicates = e.duplicates
+	child.logOptions = e.logOptions
+	return child
+}
+
+// Child returns an EnvSet overlaying DefaultEnv, e.g. to hold the per-request
+// overrides of a multi-tenant service.
+func Child() *EnvSet {
+	return DefaultEnv.Child()
+}
+
+// Set sets the variable name to value, as if it had been read from the
+// environment, and reports a *ParseError if value is invalid. A variable of a
+// parent EnvSet is first copied into e. The origin of the variable becomes
+// OriginOverride, and Parse keeps the value. It returns ErrUndefined if there
+// is no such variable.
+func (e *EnvSet) Set(name, value string) error {
+	e.Lock()
+	defer e.Unlock()
+	v, own := e.lookup(name)
+	if !own {
+		var ok bool
+		if e.parent != nil {
+			v, ok = e.parent.Lookup(name)
+		}
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUndefined, name)
+		}
+		v = v.copy()
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.Value.Set(value); err != nil {
+		return &ParseError{Name: v.Name, Value: value, Err: err}
+	}
+	v.resolved = true
+	v.origin = OriginOverride
+	if !own {
+		if e.vars == nil {
+			e.vars = make(map[string]*ConfigVar)
+		}
+		e.vars[v.Name] = v
+	}
+	return nil
+}
+
+// Set sets the variable name of DefaultEnv to value, as if it had been read
+// from the environment, and reports a *ParseError if value is invalid. Parse
+// keeps the value.
+func Set(name, value string) error {
+	return DefaultEnv.Set(name, value)
+}
+
+// copy returns a ConfigVar declaring the same variable with its own copy of
+// the Value. Parsing the copy restores the value v had when it was copied.
+func (v *ConfigVar) copy() *ConfigVar {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	value := copyValue(v.Value)
+	return &ConfigVar{
+		Name:        v.Name,
+		Description: v.Description,
+		Value:       value,
+		Default:     v.Default,
+		Secret:      v.Secret,
+		Options:     v.Options,
+		Required:    v.Required,
+		Aliases:     v.Aliases,
+		Deprecated:  v.Deprecated,
+		ReplacedBy:  v.ReplacedBy,
+		reset:       saveValue(value),
+		resolved:    v.resolved,
+		origin:      v.origin,
+	}
+}
+
+// copyValue returns a shallow copy of value, or value itself if it is not a
+// pointer. As with saveValue, this is enough for Values whose Set replaces
+// their contents.
+func copyValue(value Value) Value {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return value
+	}
+	cp := reflect.New(rv.Elem().Type())
+	cp.Elem().Set(rv.Elem())
+	return cp.Interface().(Value)
+}
@@ -0,0 +1,116 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChild(t *testing.T) {
+	parent := NewEnvSet("test")
+	parent.SetSource(MapSource{"APP_LIMIT": "10"})
+	parent.Int("app_limit", 5, "Request limit")
+	parent.Duration("app_timeout", time.Second, "Request timeout")
+
+	child := parent.Child()
+	limit, err := child.GetInt("app_limit")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, limit)
+
+	assert.NoError(t, child.Set("app_limit", "20"))
+	limit, _ = child.GetInt("APP_LIMIT")
+	assert.Equal(t, 20, limit)
+	assert.Equal(t, OriginOverride, child.Var("app_limit").Origin())
+
+	// The parent is not modified.
+	limit, _ = parent.GetInt("app_limit")
+	assert.Equal(t, 10, limit)
+	assert.Equal(t, "APP_LIMIT", parent.Var("app_limit").Origin())
+
+	timeout, err := child.GetDuration("app_timeout")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, timeout)
+	assert.Same(t, parent.Var("app_timeout"), child.Var("app_timeout"))
+	assert.Len(t, child.Vars(), 2)
+
+	var buf bytes.Buffer
+	child.PrintEnv(&buf, true, false)
+	assert.Equal(t, "export APP_LIMIT=\"20\"\nexport APP_TIMEOUT=\"1s\"\n", buf.String())
+}
+
+func TestChildSetErrors(t *testing.T) {
+	parent := NewEnvSet("test")
+	parent.Int("app_limit", 5, "Request limit")
+	child := parent.Child()
+
+	assert.True(t, errors.Is(child.Set("app_missing", "1"), ErrUndefined))
+
+	var perr *ParseError
+	assert.ErrorAs(t, child.Set("app_limit", "many"), &perr)
+	assert.Equal(t, "APP_LIMIT", perr.Name)
+	assert.Same(t, parent.Var("app_limit"), child.Var("app_limit"))
+	assert.NoError(t, child.Err())
+}
+
+func TestChildOfChild(t *testing.T) {
+	parent := NewEnvSet("test")
+	parent.String("app_tier", "free", "")
+	parent.String("app_region", "us", "")
+
+	tenant := parent.Child()
+	assert.NoError(t, tenant.Set("app_tier", "pro"))
+	request := tenant.Child()
+	assert.NoError(t, request.Set("app_region", "eu"))
+
+	tier, _ := request.GetString("app_tier")
+	region, _ := request.GetString("app_region")
+	assert.Equal(t, "pro", tier)
+	assert.Equal(t, "eu", region)
+	region, _ = tenant.GetString("app_region")
+	assert.Equal(t, "us", region)
+}
+
+func TestChildConcurrent(t *testing.T) {
+	parent := NewEnvSet("test")
+	parent.Int("app_limit", 5, "")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			child := parent.Child()
+			child.Set("app_limit", "7")
+			child.GetInt("app_limit")
+			parent.GetInt("app_limit")
+		}()
+	}
+	wg.Wait()
+	limit, _ := parent.GetInt("app_limit")
+	assert.Equal(t, 5, limit)
+}
+
+func TestSetOwnVar(t *testing.T) {
+	set := NewEnvSet("test")
+	v := set.Int("app_limit", 5, "")
+	assert.Equal(t, 5, v)
+	assert.NoError(t, set.Set("app_limit", "6"))
+	assert.Equal(t, 6, set.Var("app_limit").Get())
+}
+
+func TestSetKeptByParse(t *testing.T) {
+	t.Setenv("APP_LIMIT", "7")
+	parent := NewEnvSet("test")
+	parent.Int("app_limit", 5, "")
+	child := parent.Child()
+	assert.NoError(t, child.Set("app_limit", "6"))
+
+	assert.NoError(t, child.Parse())
+	assert.Equal(t, 6, child.Var("app_limit").Get())
+	assert.Equal(t, OriginOverride, child.Var("app_limit").Origin())
+	assert.Equal(t, 7, parent.Var("app_limit").Get())
+}
@@ -0,0 +1,13 @@
+// Command envcheck reports misuse of the env API. See package envcheck for
+// the checks. It runs standalone or as a go vet tool:
+//
+//	envcheck ./...
+//	go vet -vettool=$(which envcheck) ./...
+package main
+
+import (
+	"github.com/mattaitchison/env/envcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() { singlechecker.Main(envcheck.Analyzer) }
@@ -0,0 +1,386 @@
+// Command envdoc prints the environment variables a Go program declares with
+// env, without running it. It loads the named packages and the packages of the
+// main module they import, and finds calls to the env functions and EnvSet
+// methods that declare variables, such as env.String or set.Int, extracting
+// their names, types, defaults and descriptions.
+//
+// Usage:
+//
+//	envdoc [-format markdown|json] [packages]
+//
+// Names are shown uppercased, as with the default name mapper. Types are the
+// names PrintDefaults shows, such as duration, except for variables declared
+// with NewVar, which show the Go type of their Value. Constant arguments are
+// shown as PrintDefaults shows them, such as 5s for 5*time.Second, and other
+// arguments as source. Positions are relative to the module root.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattaitchison/env"
+	"golang.org/x/tools/go/packages"
+)
+
+const envPath = "github.com/mattaitchison/env"
+
+// Var is a variable declaration found in the source.
+type Var struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default"`
+	Description string   `json:"description"`
+	Options     []string `json:"options,omitempty"`
+	Secret      bool     `json:"secret,omitempty"`
+	Position    string   `json:"position"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("envdoc: ")
+	format := flag.String("format", "markdown", "output `format`: markdown or json")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: envdoc [-format markdown|json] [packages]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	vars, err := load(patterns...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch *format {
+	case "markdown":
+		err = writeMarkdown(os.Stdout, vars)
+	case "json":
+		err = writeJSON(os.Stdout, vars)
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load returns the variables declared by the packages matching patterns and
+// their dependencies within the same module, sorted by name.
+func load(patterns ...string) ([]Var, error) {
+	paths, err := modulePackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo |
+			packages.NeedModule,
+	}
+	pkgs, err := packages.Load(cfg, paths...)
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("errors loading packages")
+	}
+
+	var vars []Var
+	for _, pkg := range pkgs {
+		var root string
+		if pkg.Module != nil {
+			root = pkg.Module.Dir
+		}
+		vars = append(vars, extract(pkg.Fset, pkg.TypesInfo, pkg.Syntax, root)...)
+	}
+	sort.SliceStable(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars, nil
+}
+
+// modulePackages returns the import paths of the packages matching patterns
+// and their dependencies in the main module. Only the import graph is loaded,
+// so that the full load that follows does not type-check the standard library
+// from source.
+func modulePackages(patterns []string) ([]string, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("errors loading packages")
+	}
+
+	roots := map[*packages.Package]bool{}
+	for _, pkg := range pkgs {
+		roots[pkg] = true
+	}
+	var paths []string
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		// env itself only declares variables on behalf of its callers.
+		if pkg.PkgPath == envPath {
+			return
+		}
+		if roots[pkg] || pkg.Module != nil && pkg.Module.Main {
+			paths = append(paths, pkg.PkgPath)
+		}
+	})
+	return paths, nil
+}
+
+// extract returns the variables declared in files.
+func extract(fset *token.FileSet, info *types.Info, files []*ast.File, root string) []Var {
+	var vars []Var
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fn := callee(info, call)
+			if fn == nil {
+				return true
+			}
+			if v, ok := declaration(fset, info, fn, call, root); ok {
+				vars = append(vars, v)
+			}
+			return true
+		})
+	}
+	return vars
+}
+
+// callee returns the env function or EnvSet method called by call, or nil.
+func callee(info *types.Info, call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+	fn, ok := info.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != envPath {
+		return nil
+	}
+	return fn
+}
+
+// declaration describes the variable declared by a call to fn, which must have
+// name and description parameters, as the declaring functions of env do.
+func declaration(fset *token.FileSet, info *types.Info, fn *types.Func, call *ast.CallExpr, root string) (Var, bool) {
+	sig := fn.Type().(*types.Signature)
+	args := map[string]ast.Expr{}
+	for i := 0; i < sig.Params().Len() && i < len(call.Args); i++ {
+		args[sig.Params().At(i).Name()] = call.Args[i]
+	}
+	name, description := args["name"], args["description"]
+	if name == nil || description == nil || sig.Results().Len() == 0 {
+		return Var{}, false
+	}
+
+	qualifier := func(p *types.Package) string { return p.Name() }
+	typ, ok := typeNames[fn.Name()]
+	if !ok {
+		typ = types.TypeString(sig.Results().At(0).Type(), qualifier)
+	}
+	v := Var{
+		Name:        strings.ToUpper(featurePrefix(fn) + text(fset, info, name)),
+		Type:        typ,
+		Description: text(fset, info, description),
+		Secret:      fn.Name() == "Secret",
+		Position:    position(fset.Position(call.Pos()), root),
+	}
+	if value := args["value"]; value != nil {
+		// NewVar and TryNewVar take the Value itself.
+		v.Type = types.TypeString(info.TypeOf(value), qualifier)
+	}
+	if def := args["defaultVal"]; def != nil {
+		v.Default = text(fset, info, def)
+	}
+	if opts, ok := args["options"].(*ast.CompositeLit); ok {
+		for _, elt := range opts.Elts {
+			v.Options = append(v.Options, text(fset, info, elt))
+		}
+	}
+	return v, true
+}
+
+// typeNames maps the declaring functions of env to the Type of the Values they
+// declare, so the types match those shown by PrintDefaults.
+var typeNames = map[string]string{
+	"String":          "string",
+	"StringOption":    "string",
+	"Secret":          "string",
+	"StringList":      "list",
+	"StringMap":       "map",
+	"StringMapSep":    "map",
+	"Bool":            "bool",
+	"Float32":         "float32",
+	"Float64":         "float64",
+	"Float64Option":   "float64",
+	"Float64Map":      "float64map",
+	"Float64MapSep":   "float64map",
+	"Int":             "int",
+	"IntOption":       "int",
+	"IntMap":          "intmap",
+	"IntMapSep":       "intmap",
+	"Int8":            "int8",
+	"Int16":           "int16",
+	"Int32":           "int32",
+	"Int64":           "int64",
+	"Int64Option":     "int64",
+	"Uint":            "uint",
+	"UintOption":      "uint",
+	"Uint8":           "uint8",
+	"Uint16":          "uint16",
+	"Uint32":          "uint32",
+	"Uint64":          "uint64",
+	"Uint64Option":    "uint64",
+	"Duration":        "duration",
+	"Time":            "time",
+	"TimeLayout":      "time",
+	"Location":        "location",
+	"Cron":            "cron",
+	"Size":            "size",
+	"SizeRange":       "size",
+	"Percentage":      "percent",
+	"PercentageRange": "percent",
+	"Frequency":       "rate",
+	"FrequencyRange":  "rate",
+	"IP":              "ip",
+	"IPAddr":          "ip",
+	"IPNet":           "cidr",
+	"IPPrefix":        "cidr",
+	"HostPort":        "hostport",
+	"TCPAddr":         "tcpaddr",
+	"UDPAddr":         "udpaddr",
+	"URL":             "url",
+	"Feature":         "feature",
+	"FeatureRollout":  "feature",
+}
+
+// featurePrefix returns the prefix of the variable declared by fn, which is
+// env.FeaturePrefix for feature flags.
+func featurePrefix(fn *types.Func) string {
+	if fn.Name() == "Feature" || fn.Name() == "FeatureRollout" {
+		return env.FeaturePrefix
+	}
+	return ""
+}
+
+// text returns the value of expr if it is a string constant, or its source.
+func text(fset *token.FileSet, info *types.Info, expr ast.Expr) string {
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+		return constText(tv.Type, tv.Value)
+	}
+	var buf bytes.Buffer
+	format.Node(&buf, fset, expr)
+	return buf.String()
+}
+
+// constText formats the constant c of type typ as the String method of the
+// Value holding it would.
+func constText(typ types.Type, c constant.Value) string {
+	switch c.Kind() {
+	case constant.String:
+		return constant.StringVal(c)
+	case constant.Bool:
+		return strconv.FormatBool(constant.BoolVal(c))
+	}
+	if named, ok := types.Unalias(typ).(*types.Named); ok && named.Obj().Pkg() != nil {
+		switch named.Obj().Pkg().Path() + "." + named.Obj().Name() {
+		case "time.Duration":
+			if n, exact := constant.Int64Val(c); exact {
+				return time.Duration(n).String()
+			}
+		case envPath + ".ByteSize":
+			if n, exact := constant.Uint64Val(c); exact {
+				return env.ByteSize(n).String()
+			}
+		case envPath + ".Percent":
+			f, _ := constant.Float64Val(c)
+			return env.Percent(f).String()
+		}
+	}
+	if basic, ok := typ.Underlying().(*types.Basic); ok && basic.Info()&types.IsFloat != 0 {
+		f, _ := constant.Float64Val(c)
+		if basic.Kind() == types.Float32 {
+			return strconv.FormatFloat(float64(float32(f)), 'g', -1, 32)
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return c.ExactString()
+}
+
+// position returns pos with its file name relative to root when it is inside
+// it, so the output does not depend on where the module is checked out.
+func position(pos token.Position, root string) string {
+	if root == "" {
+		return pos.String()
+	}
+	if rel, err := filepath.Rel(root, pos.Filename); err == nil && filepath.IsLocal(rel) {
+		pos.Filename = filepath.ToSlash(rel)
+	}
+	return pos.String()
+}
+
+func writeMarkdown(w io.Writer, vars []Var) error {
+	var b strings.Builder
+	b.WriteString("| Name | Type | Default | Description |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, v := range vars {
+		desc := v.Description
+		if len(v.Options) > 0 {
+			desc += " One of: " + strings.Join(v.Options, ", ") + "."
+		}
+		if v.Secret {
+			desc += " (secret)"
+		}
+		fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s |\n",
+			v.Name, v.Type, code(v.Default), escape(strings.TrimSpace(desc)))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + escape(s) + "`"
+}
+
+func escape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+func writeJSON(w io.Writer, vars []Var) error {
+	if vars == nil {
+		vars = []Var{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(vars)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/mattaitchison/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	vars, err := load("./testdata/example")
+	if !assert.NoError(t, err) {
+		return
+	}
+	for i := range vars {
+		assert.Regexp(t, `^cmd/envdoc/testdata/example/(internal/store/store|example)\.go:\d+:\d+$`, vars[i].Position)
+		vars[i].Position = ""
+	}
+	assert.Equal(t, []Var{
+		{Name: "EXAMPLE_BUFFER", Type: "size", Default: "64MiB", Description: "Buffer size"},
+		{Name: "EXAMPLE_DEBUG", Type: "bool", Default: "false", Description: "Enable | debug output"},
+		{Name: "EXAMPLE_MODE", Type: "string", Default: "fast", Description: "Processing mode", Options: []string{"fast", "safe"}},
+		{Name: "EXAMPLE_PORT", Type: "int", Default: "8080", Description: "Listen port"},
+		{Name: "EXAMPLE_TIMEOUT", Type: "duration", Default: "5s", Description: "Request timeout"},
+		{Name: "EXAMPLE_TOKEN", Type: "string", Description: "API token", Secret: true},
+		{Name: "FEATURE_EXAMPLE_BETA", Type: "feature", Default: "false", Description: "Beta features"},
+		{Name: "STORE_DSN", Type: "string", Default: "sqlite://", Description: "Database connection string"},
+	}, vars)
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	writeMarkdown(&buf, []Var{
+		{Name: "A", Type: "string", Default: "x", Description: "Mode | kind", Options: []string{"x", "y"}},
+		{Name: "B", Type: "string", Description: "Token", Secret: true},
+	})
+	assert.Equal(t, "| Name | Type | Default | Description |\n"+
+		"| --- | --- | --- | --- |\n"+
+		"| `A` | `string` | `x` | Mode \\| kind One of: x, y. |\n"+
+		"| `B` | `string` |  | Token (secret) |\n", buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	writeJSON(&buf, nil)
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestTypeNames(t *testing.T) {
+	loc, _ := time.LoadLocation("UTC")
+	declare := map[string]func(set *env.EnvSet){
+		"String":          func(set *env.EnvSet) { set.String("v", "", "") },
+		"StringOption":    func(set *env.EnvSet) { set.StringOption("v", "a", []string{"a"}, "") },
+		"Secret":          func(set *env.EnvSet) { set.Secret("v", "") },
+		"StringList":      func(set *env.EnvSet) { set.StringList("v", nil, "") },
+		"StringMap":       func(set *env.EnvSet) { set.StringMap("v", nil, "") },
+		"StringMapSep":    func(set *env.EnvSet) { set.StringMapSep("v", nil, ";", "=", "") },
+		"Bool":            func(set *env.EnvSet) { set.Bool("v", false, "") },
+		"Float32":         func(set *env.EnvSet) { set.Float32("v", 0, "") },
+		"Float64":         func(set *env.EnvSet) { set.Float64("v", 0, "") },
+		"Float64Option":   func(set *env.EnvSet) { set.Float64Option("v", 0, []float64{0}, "") },
+		"Float64Map":      func(set *env.EnvSet) { set.Float64Map("v", nil, "") },
+		"Float64MapSep":   func(set *env.EnvSet) { set.Float64MapSep("v", nil, ";", "=", "") },
+		"Int":             func(set *env.EnvSet) { set.Int("v", 0, "") },
+		"IntOption":       func(set *env.EnvSet) { set.IntOption("v", 0, []int{0}, "") },
+		"IntMap":          func(set *env.EnvSet) { set.IntMap("v", nil, "") },
+		"IntMapSep":       func(set *env.EnvSet) { set.IntMapSep("v", nil, ";", "=", "") },
+		"Int8":            func(set *env.EnvSet) { set.Int8("v", 0, "") },
+		"Int16":           func(set *env.EnvSet) { set.Int16("v", 0, "") },
+		"Int32":           func(set *env.EnvSet) { set.Int32("v", 0, "") },
+		"Int64":           func(set *env.EnvSet) { set.Int64("v", 0, "") },
+		"Int64Option":     func(set *env.EnvSet) { set.Int64Option("v", 0, []int64{0}, "") },
+		"Uint":            func(set *env.EnvSet) { set.Uint("v", 0, "") },
+		"UintOption":      func(set *env.EnvSet) { set.UintOption("v", 0, []uint{0}, "") },
+		"Uint8":           func(set *env.EnvSet) { set.Uint8("v", 0, "") },
+		"Uint16":          func(set *env.EnvSet) { set.Uint16("v", 0, "") },
+		"Uint32":          func(set *env.EnvSet) { set.Uint32("v", 0, "") },
+		"Uint64":          func(set *env.EnvSet) { set.Uint64("v", 0, "") },
+		"Uint64Option":    func(set *env.EnvSet) { set.Uint64Option("v", 0, []uint64{0}, "") },
+		"Duration":        func(set *env.EnvSet) { set.Duration("v", 0, "") },
+		"Time":            func(set *env.EnvSet) { set.Time("v", time.Time{}, "") },
+		"TimeLayout":      func(set *env.EnvSet) { set.TimeLayout("v", time.Time{}, []string{time.RFC3339}, "") },
+		"Location":        func(set *env.EnvSet) { set.Location("v", loc, "") },
+		"Cron":            func(set *env.EnvSet) { set.Cron("v", env.MustParseSchedule("@daily"), "") },
+		"Size":            func(set *env.EnvSet) { set.Size("v", 0, "") },
+		"SizeRange":       func(set *env.EnvSet) { set.SizeRange("v", 0, 0, env.KB, "") },
+		"Percentage":      func(set *env.EnvSet) { set.Percentage("v", 0, "") },
+		"PercentageRange": func(set *env.EnvSet) { set.PercentageRange("v", 0, 0, 100, "") },
+		"Frequency":       func(set *env.EnvSet) { set.Frequency("v", env.Rate{Count: 1, Per: time.Second}, "") },
+		"FrequencyRange": func(set *env.EnvSet) {
+			r := env.Rate{Count: 1, Per: time.Second}
+			set.FrequencyRange("v", r, r, r, "")
+		},
+		"IP":             func(set *env.EnvSet) { set.IP("v", nil, "") },
+		"IPAddr":         func(set *env.EnvSet) { set.IPAddr("v", netip.Addr{}, "") },
+		"IPNet":          func(set *env.EnvSet) { set.IPNet("v", nil, "") },
+		"IPPrefix":       func(set *env.EnvSet) { set.IPPrefix("v", netip.Prefix{}, "") },
+		"HostPort":       func(set *env.EnvSet) { set.HostPort("v", "", "80", "") },
+		"TCPAddr":        func(set *env.EnvSet) { set.TCPAddr("v", "", "") },
+		"UDPAddr":        func(set *env.EnvSet) { set.UDPAddr("v", "", "") },
+		"URL":            func(set *env.EnvSet) { set.URL("v", nil, nil, "") },
+		"Feature":        func(set *env.EnvSet) { set.Feature("v", false, "") },
+		"FeatureRollout": func(set *env.EnvSet) { set.FeatureRollout("v", env.Rollout{}, "") },
+	}
+	assert.Len(t, typeNames, len(declare))
+	for name, fn := range declare {
+		set := env.NewEnvSet("test")
+		fn(set)
+		set.VisitAll(func(v *env.ConfigVar) {
+			assert.Equal(t, typeNames[name], v.Type(), name)
+		})
+	}
+}
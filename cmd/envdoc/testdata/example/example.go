@@ -0,0 +1,24 @@
+package example
+
+import (
+	"time"
+
+	"github.com/mattaitchison/env"
+	_ "github.com/mattaitchison/env/cmd/envdoc/testdata/example/internal/store"
+)
+
+const portName = "example_port"
+
+var (
+	port    = env.Int(portName, 8080, "Listen port")
+	timeout = env.Duration("example_timeout", 5*time.Second, "Request timeout")
+	mode    = env.StringOption("example_mode", "fast", []string{"fast", "safe"}, "Processing mode")
+	token   = env.Secret("example_token", "API token")
+)
+
+func configure(set *env.EnvSet) {
+	set.Bool("example_debug", false, "Enable | debug output")
+	set.Feature("example_beta", false, "Beta features")
+	set.Size("example_buffer", 64*env.MiB, "Buffer size")
+	env.BoolWords(nil, nil)
+}
@@ -0,0 +1,5 @@
+package store
+
+import "github.com/mattaitchison/env"
+
+var dsn = env.String("store_dsn", "sqlite://", "Database connection string")
@@ -0,0 +1,342 @@
+// Command envgen generates a typed configuration struct from a schema of
+// variables, so the definitions can be shared with tooling outside Go. The
+// schema is a YAML or JSON list of variables:
+//
+//	# config.yaml
+//	- name: app_port
+//	  type: int
+//	  default: 8080
+//	  desc: Listen port
+//	  options: [80, 8080]
+//	- name: app_token
+//	  type: string
+//	  desc: API token
+//	  secret: true
+//
+// The generated file declares a struct with a field per variable and a function
+// declaring the variables on an EnvSet, named Load followed by the struct name,
+// such as LoadConfig:
+//
+//	//go:generate envgen -type Config config.yaml
+//
+// Supported types are string, bool, int, int8, int16, int32, int64, uint,
+// uint8, uint16, uint32, uint64, float32, float64, duration, list and size.
+// Options are supported for string, int, int64, uint, uint64 and float64, and
+// only strings can be secret. Field names are derived from the variable names
+// unless given with field.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mattaitchison/env"
+	"gopkg.in/yaml.v3"
+)
+
+// Var is a variable in the schema.
+type Var struct {
+	Name    string   `yaml:"name"`
+	Type    string   `yaml:"type"`
+	Default string   `yaml:"default"`
+	Desc    string   `yaml:"desc"`
+	Secret  bool     `yaml:"secret"`
+	Options []string `yaml:"options"`
+	Field   string   `yaml:"field"`
+}
+
+// kind describes how a schema type is declared.
+type kind struct {
+	goType  string
+	fn      string
+	option  bool
+	literal func(s string) (string, error)
+}
+
+var kinds = map[string]kind{
+	"string":   {"string", "String", true, quote},
+	"bool":     {"bool", "Bool", false, boolean},
+	"int":      {"int", "Int", true, integer(strconv.IntSize)},
+	"int8":     {"int8", "Int8", false, integer(8)},
+	"int16":    {"int16", "Int16", false, integer(16)},
+	"int32":    {"int32", "Int32", false, integer(32)},
+	"int64":    {"int64", "Int64", true, integer(64)},
+	"uint":     {"uint", "Uint", true, unsigned(strconv.IntSize)},
+	"uint8":    {"uint8", "Uint8", false, unsigned(8)},
+	"uint16":   {"uint16", "Uint16", false, unsigned(16)},
+	"uint32":   {"uint32", "Uint32", false, unsigned(32)},
+	"uint64":   {"uint64", "Uint64", true, unsigned(64)},
+	"float32":  {"float32", "Float32", false, float(32)},
+	"float64":  {"float64", "Float64", true, float(64)},
+	"duration": {"time.Duration", "Duration", false, duration},
+	"list":     {"[]string", "StringList", false, list},
+	"size":     {"env.ByteSize", "Size", false, size},
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("envgen: ")
+	output := flag.String("o", "", "output `file` (default <schema>_env.go)")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package `name` of the generated file")
+	typ := flag.String("type", "Config", "`name` of the generated struct")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: envgen [flags] schema.yaml\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *pkg == "" {
+		*pkg = "config"
+	}
+
+	schema := flag.Arg(0)
+	data, err := os.ReadFile(schema)
+	if err != nil {
+		log.Fatal(err)
+	}
+	vars, err := parseSchema(data)
+	if err != nil {
+		log.Fatalf("%s: %v", schema, err)
+	}
+	src, err := generate(*pkg, *typ, filepath.Base(schema), vars)
+	if err != nil {
+		log.Fatalf("%s: %v", schema, err)
+	}
+
+	if *output == "" {
+		*output = strings.TrimSuffix(schema, filepath.Ext(schema)) + "_env.go"
+	}
+	if err := os.WriteFile(*output, src, 0o666); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseSchema decodes a YAML or JSON list of variables.
+func parseSchema(data []byte) ([]Var, error) {
+	var vars []Var
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&vars); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// generate returns the formatted source of a file declaring the struct typ
+// and its Load function, named Load followed by typ, for vars.
+func generate(pkg, typ, schema string, vars []Var) ([]byte, error) {
+	var fields, decls bytes.Buffer
+	usesTime := false
+	names := map[string]bool{}
+	for _, v := range vars {
+		field, decl, err := declare(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v.Name, err)
+		}
+		if names[field] {
+			return nil, fmt.Errorf("%s: duplicate field %s", v.Name, field)
+		}
+		names[field] = true
+		usesTime = usesTime || kinds[v.Type].goType == "time.Duration"
+
+		if v.Desc != "" {
+			fmt.Fprintf(&fields, "// %s\n", strings.ReplaceAll(v.Desc, "\n", "\n// "))
+		}
+		fmt.Fprintf(&fields, "%s %s\n", field, kinds[v.Type].goType)
+		fmt.Fprintf(&decls, "%s: %s,\n", field, decl)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by envgen from %s; DO NOT EDIT.\n\n", schema)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import (\n")
+	if usesTime {
+		b.WriteString("\"time\"\n\n")
+	}
+	b.WriteString("\"github.com/mattaitchison/env\"\n)\n\n")
+	fmt.Fprintf(&b, "// %s holds the variables declared in %s.\n", typ, schema)
+	fmt.Fprintf(&b, "type %s struct {\n%s}\n\n", typ, fields.Bytes())
+	fmt.Fprintf(&b, "// Load%s declares the variables of %s on set and returns their values,\n", typ, typ)
+	b.WriteString("// with the errors of set.\n")
+	fmt.Fprintf(&b, "func Load%s(set *env.EnvSet) (*%s, error) {\n", typ, typ)
+	fmt.Fprintf(&b, "cfg := &%s{\n%s}\n", typ, decls.Bytes())
+	b.WriteString("return cfg, set.Err()\n}\n")
+	return format.Source(b.Bytes())
+}
+
+// declare returns the field name of v and the call declaring it.
+func declare(v Var) (field, call string, err error) {
+	if v.Name == "" {
+		return "", "", fmt.Errorf("missing name")
+	}
+	field = v.Field
+	if field == "" {
+		field = fieldName(v.Name)
+	}
+	if !token.IsIdentifier(field) || !token.IsExported(field) {
+		return "", "", fmt.Errorf("invalid field name %q", field)
+	}
+
+	k, ok := kinds[v.Type]
+	if !ok {
+		return "", "", fmt.Errorf("unknown type %q", v.Type)
+	}
+	name, desc := strconv.Quote(v.Name), strconv.Quote(v.Desc)
+	if v.Secret {
+		if v.Type != "string" || v.Default != "" || len(v.Options) > 0 {
+			return "", "", fmt.Errorf("secret must be a string without default or options")
+		}
+		return field, fmt.Sprintf("set.Secret(%s, %s)", name, desc), nil
+	}
+
+	def := v.Default
+	if def == "" && v.Type != "string" && v.Type != "list" {
+		def = zero(v.Type)
+	}
+	lit, err := k.literal(def)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid default: %w", err)
+	}
+	if len(v.Options) == 0 {
+		return field, fmt.Sprintf("set.%s(%s, %s, %s)", k.fn, name, lit, desc), nil
+	}
+
+	if !k.option {
+		return "", "", fmt.Errorf("options are not supported for %s", v.Type)
+	}
+	opts := make([]string, len(v.Options))
+	for i, o := range v.Options {
+		if opts[i], err = k.literal(o); err != nil {
+			return "", "", fmt.Errorf("invalid option: %w", err)
+		}
+	}
+	return field, fmt.Sprintf("set.%sOption(%s, %s, []%s{%s}, %s)",
+		k.fn, name, lit, k.goType, strings.Join(opts, ", "), desc), nil
+}
+
+func zero(typ string) string {
+	if typ == "bool" {
+		return "false"
+	}
+	return "0"
+}
+
+// fieldName converts a variable name such as app_port to AppPort.
+func fieldName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		part = strings.ToLower(part)
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	field := b.String()
+	if field != "" && !unicode.IsLetter(rune(field[0])) {
+		field = "V" + field
+	}
+	return field
+}
+
+func quote(s string) (string, error) { return strconv.Quote(s), nil }
+
+// The literal funcs format the parsed value rather than copying s, so that
+// defaults such as "T" or "0x10" become valid Go.
+
+func boolean(s string) (string, error) {
+	b, err := env.ParseBool(s)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(b), nil
+}
+
+func integer(bits int) func(string) (string, error) {
+	return func(s string) (string, error) {
+		n, err := strconv.ParseInt(s, 0, bits)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(n, 10), nil
+	}
+}
+
+func unsigned(bits int) func(string) (string, error) {
+	return func(s string) (string, error) {
+		n, err := strconv.ParseUint(s, 0, bits)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(n, 10), nil
+	}
+}
+
+func float(bits int) func(string) (string, error) {
+	return func(s string) (string, error) {
+		f, err := strconv.ParseFloat(s, bits)
+		if err != nil {
+			return "", err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return "", fmt.Errorf("%s is not finite", s)
+		}
+		return strconv.FormatFloat(f, 'g', -1, bits), nil
+	}
+}
+
+// duration converts a duration such as 1m30s to an expression in the largest
+// exact unit, 90 * time.Second.
+func duration(s string) (string, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return "", err
+	}
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d != 0 && d%u.d == 0 {
+			return fmt.Sprintf("%d * %s", d/u.d, u.name), nil
+		}
+	}
+	return fmt.Sprintf("%d", int64(d)), nil
+}
+
+func list(s string) (string, error) {
+	if s == "" {
+		return "nil", nil
+	}
+	parts := strings.Split(s, ",")
+	for i, p := range parts {
+		parts[i] = strconv.Quote(strings.TrimSpace(p))
+	}
+	return "[]string{" + strings.Join(parts, ", ") + "}", nil
+}
+
+func size(s string) (string, error) {
+	b, err := env.ParseByteSize(s)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d", uint64(b)), nil
+}
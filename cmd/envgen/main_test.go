@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	data, err := os.ReadFile("testdata/config.yaml")
+	if !assert.NoError(t, err) {
+		return
+	}
+	vars, err := parseSchema(data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	src, err := generate("config", "Config", "config.yaml", vars)
+	if !assert.NoError(t, err) {
+		return
+	}
+	want, err := os.ReadFile("testdata/config_env.go.golden")
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(src))
+}
+
+func TestParseSchemaJSON(t *testing.T) {
+	vars, err := parseSchema([]byte(`[{"name": "port", "type": "int", "default": "80", "desc": "Port"}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Var{{Name: "port", Type: "int", Default: "80", Desc: "Port"}}, vars)
+
+	_, err = parseSchema([]byte(`[{"name": "port", "typ": "int"}]`))
+	assert.Error(t, err)
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	tests := map[string]Var{
+		"unknown type":    {Name: "a", Type: "complex"},
+		"invalid default": {Name: "a", Type: "int8", Default: "300"},
+		"infinite float":  {Name: "a", Type: "float64", Default: "inf"},
+		"nan float":       {Name: "a", Type: "float32", Default: "NaN"},
+		"invalid bool":    {Name: "a", Type: "bool", Default: "maybe"},
+		"invalid option":  {Name: "a", Type: "int", Options: []string{"x"}},
+		"option type":     {Name: "a", Type: "bool", Options: []string{"true"}},
+		"secret default":  {Name: "a", Type: "string", Default: "x", Secret: true},
+		"secret type":     {Name: "a", Type: "int", Secret: true},
+		"field name":      {Name: "a", Type: "int", Field: "lower"},
+		"missing name":    {Type: "int"},
+	}
+	for name, v := range tests {
+		_, err := generate("config", "Config", "config.yaml", []Var{v})
+		assert.Error(t, err, name)
+	}
+
+	_, err := generate("config", "Config", "config.yaml", []Var{
+		{Name: "app.port", Type: "int"},
+		{Name: "APP_PORT", Type: "int"},
+	})
+	assert.EqualError(t, err, "APP_PORT: duplicate field AppPort")
+}
+
+func TestFieldName(t *testing.T) {
+	assert.Equal(t, "AppPort", fieldName("APP_PORT"))
+	assert.Equal(t, "DbHostName", fieldName("db.host-name"))
+	assert.Equal(t, "V2fa", fieldName("2fa"))
+}
+
+func TestDefaultLiterals(t *testing.T) {
+	tests := []struct {
+		typ, def, want string
+	}{
+		{"bool", "1", "true"},
+		{"bool", "T", "true"},
+		{"bool", "yes", "true"},
+		{"bool", "off", "false"},
+		{"int", "0x10", "16"},
+		{"int", "+5", "5"},
+		{"uint8", "0b11", "3"},
+		{"float64", "1e6", "1e+06"},
+		{"float32", "0.5", "0.5"},
+		{"list", "a, b", `[]string{"a", "b"}`},
+		{"size", "10000PiB", "11258999068426240000"},
+	}
+	for _, tt := range tests {
+		_, call, err := declare(Var{Name: "a", Type: tt.typ, Default: tt.def})
+		if assert.NoError(t, err, tt.def) {
+			assert.Contains(t, call, `("a", `+tt.want+`, "")`, tt.def)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package env
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying e, usually a Child holding
+// request-scoped overrides.
+func NewContext(ctx context.Context, e *EnvSet) context.Context {
+	return context.WithValue(ctx, contextKey{}, e)
+}
+
+// FromContext returns the EnvSet carried by ctx, or DefaultEnv if there is
+// none, so that its getters can always be used:
+//
+//	limit, err := env.FromContext(ctx).GetInt("rate_limit")
+func FromContext(ctx context.Context) *EnvSet {
+	if e, ok := ctx.Value(contextKey{}).(*EnvSet); ok {
+		return e
+	}
+	return DefaultEnv
+}
@@ -0,0 +1,17 @@
+package env
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	ResetForTesting()
+	assert.Same(t, DefaultEnv, FromContext(context.Background()))
+
+	child := Child()
+	ctx := NewContext(context.Background(), child)
+	assert.Same(t, child, FromContext(ctx))
+}
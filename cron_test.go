@@ -0,0 +1,45 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, time.May, 15, 10, 30, 45, 0, time.UTC)
+	tests := map[string]time.Time{
+		"* * * * *":           time.Date(2024, time.May, 15, 10, 31, 0, 0, time.UTC),
+		"*/15 * * * *":        time.Date(2024, time.May, 15, 10, 45, 0, 0, time.UTC),
+		"30 2 * * *":          time.Date(2024, time.May, 16, 2, 30, 0, 0, time.UTC),
+		"0 9-17/4 * * *":      time.Date(2024, time.May, 15, 13, 0, 0, 0, time.UTC),
+		"0 0 * * sat,sun":     time.Date(2024, time.May, 18, 0, 0, 0, 0, time.UTC),
+		"0 0 * * 7":           time.Date(2024, time.May, 19, 0, 0, 0, 0, time.UTC),
+		"0 0 1 jan *":         time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 29 feb *":        time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"0 0 1 * mon":         time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC),
+		"0 0 */2 * mon":       time.Date(2024, time.May, 27, 0, 0, 0, 0, time.UTC),
+		"0 0 * * */2":         time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC),
+		"@hourly":             time.Date(2024, time.May, 15, 11, 0, 0, 0, time.UTC),
+		"@weekly":             time.Date(2024, time.May, 19, 0, 0, 0, 0, time.UTC),
+		"0 0 31 apr *":        {},
+		"5/20 3 15 may-jun *": time.Date(2024, time.June, 15, 3, 5, 0, 0, time.UTC),
+	}
+	for expr, expected := range tests {
+		s, err := ParseSchedule(expr)
+		if assert.NoError(t, err, expr) {
+			assert.Equal(t, expected, s.Next(from), expr)
+			assert.Equal(t, expr, s.String())
+		}
+	}
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "5-1 * * * *", "*/0 * * * *", "@often"} {
+		_, err := ParseSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+	assert.Panics(t, func() { MustParseSchedule("bad") })
+}
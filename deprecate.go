@@ -0,0 +1,121 @@
+package env
+
+import (
+	"log"
+	"strings"
+)
+
+// Logger receives warnings, such as a deprecated variable being set.
+// *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// SetLogger sets the destination for warnings. If l is nil, the standard
+// logger from the log package is used.
+func (e *EnvSet) SetLogger(l Logger) {
+	e.Lock()
+	defer e.Unlock()
+	e.logger = l
+}
+
+// SetLogger sets the destination for warnings. If l is nil, the standard
+// logger from the log package is used.
+func SetLogger(l Logger) {
+	DefaultEnv.SetLogger(l)
+}
+
+// output returns the Logger for warnings. e must be locked.
+func (e *EnvSet) output() Logger {
+	if e.logger == nil {
+		return log.Default()
+	}
+	return e.logger
+}
+
+// Alias registers oldNames as deprecated names for the variable name, e.g.
+// after a rename. When name is not set the old names are consulted in order
+// and a warning is logged if one of them is used. Alias must be called before
+// name is declared.
+func (e *EnvSet) Alias(name string, oldNames ...string) {
+	e.Lock()
+	defer e.Unlock()
+	if e.aliases == nil {
+		e.aliases = make(map[string][]string)
+	}
+	name = e.mapName(name)
+	for _, old := range oldNames {
+		e.aliases[name] = append(e.aliases[name], e.mapName(old))
+	}
+}
+
+// Alias registers oldNames as deprecated names for the variable name, e.g.
+// after a rename. When name is not set the old names are consulted in order
+// and a warning is logged if one of them is used. Alias must be called before
+// name is declared.
+func Alias(name string, oldNames ...string) {
+	DefaultEnv.Alias(name, oldNames...)
+}
+
+// Deprecate marks the variable name as deprecated in favor of replacement,
+// which may be empty. A warning is logged if the variable is set and
+// PrintDefaults shows it as deprecated. Deprecate must be called before name
+// is declared.
+func (e *EnvSet) Deprecate(name, replacement string) {
+	e.Lock()
+	defer e.Unlock()
+	if e.deprecated == nil {
+		e.deprecated = make(map[string]string)
+	}
+	if replacement != "" {
+		replacement = e.mapName(replacement)
+	}
+	e.deprecated[e.mapName(name)] = replacement
+}
+
+// Deprecate marks the variable name as deprecated in favor of replacement,
+// which may be empty. A warning is logged if the variable is set and
+// PrintDefaults shows it as deprecated. Deprecate must be called before name
+// is declared.
+func Deprecate(name, replacement string) {
+	DefaultEnv.Deprecate(name, replacement)
+}
+
+// lookupVar returns the value of v from src and the name it was found under,
+// trying v's aliases in order when v.Name is not set.
+func lookupVar(src Source, v *ConfigVar) (key, val string) {
+	for _, key := range append([]string{v.Name}, v.Aliases...) {
+		if val, _ := src.LookupEnv(key); val != "" {
+			return key, val
+		}
+	}
+	return "", ""
+}
+
+// deprecationWarnings returns the warnings for reading v from the environment
+// variable key.
+func deprecationWarnings(v *ConfigVar, key string) []string {
+	var warnings []string
+	if key != v.Name {
+		warnings = append(warnings, "env: "+key+" is deprecated, use "+v.Name)
+	}
+	if v.Deprecated {
+		if v.ReplacedBy != "" {
+			warnings = append(warnings, "env: "+v.Name+" is deprecated, use "+v.ReplacedBy)
+		} else {
+			warnings = append(warnings, "env: "+v.Name+" is deprecated")
+		}
+	}
+	return warnings
+}
+
+// usage returns the description of v followed by its deprecation, if any.
+func (v *ConfigVar) usage() string {
+	if !v.Deprecated {
+		return v.Description
+	}
+	if v.ReplacedBy != "" {
+		return strings.TrimSpace(v.Description + " (deprecated, use " + v.ReplacedBy + ")")
+	}
+	return strings.TrimSpace(v.Description + " (deprecated)")
+}
@@ -0,0 +1,71 @@
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogger []string
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	*l = append(*l, fmt.Sprintf(format, v...))
+}
+
+func TestAlias(t *testing.T) {
+	var logger testLogger
+	set := NewEnvSet("test")
+	set.SetLogger(&logger)
+	set.Alias("registrator_ttl_refresh", "registrator_ttl", "ttl")
+
+	t.Setenv("TTL", "10")
+	t.Setenv("REGISTRATOR_TTL", "20")
+	assert.Equal(t, 20, set.Int("registrator_ttl_refresh", 0, "ttl refresh"))
+	assert.Equal(t, testLogger{"env: REGISTRATOR_TTL is deprecated, use REGISTRATOR_TTL_REFRESH"}, logger)
+	assert.Equal(t, []string{"REGISTRATOR_TTL", "TTL"}, set.Var("registrator_ttl_refresh").Aliases)
+}
+
+func TestAliasNotUsed(t *testing.T) {
+	var logger testLogger
+	set := NewEnvSet("test")
+	set.SetLogger(&logger)
+	set.Alias("registrator_ttl_refresh", "registrator_ttl")
+
+	t.Setenv("REGISTRATOR_TTL", "20")
+	t.Setenv("REGISTRATOR_TTL_REFRESH", "30")
+	assert.Equal(t, 30, set.Int("registrator_ttl_refresh", 0, "ttl refresh"))
+	assert.Empty(t, logger)
+}
+
+func TestAliasParseError(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetLogger(new(testLogger))
+	set.Alias("new_name", "old_name")
+
+	t.Setenv("OLD_NAME", "ten")
+	set.Int("new_name", 1, "")
+	var perr *ParseError
+	if assert.ErrorAs(t, set.Err(), &perr) {
+		assert.Equal(t, "OLD_NAME", perr.Name)
+	}
+}
+
+func TestDeprecate(t *testing.T) {
+	var logger testLogger
+	set := NewEnvSet("test")
+	set.SetLogger(&logger)
+	set.Deprecate("registrator_ttl", "registrator_ttl_refresh")
+	set.Deprecate("registrator_legacy", "")
+
+	t.Setenv("REGISTRATOR_TTL", "20")
+	set.Int("registrator_ttl", 0, "ttl")
+	set.Bool("registrator_legacy", false, "")
+	assert.Equal(t, testLogger{"env: REGISTRATOR_TTL is deprecated, use REGISTRATOR_TTL_REFRESH"}, logger)
+
+	var buf bytes.Buffer
+	set.PrintDefaults(&buf)
+	assert.Contains(t, buf.String(), "        ttl (deprecated, use REGISTRATOR_TTL_REFRESH)\n")
+	assert.Contains(t, buf.String(), "        (deprecated)\n")
+}
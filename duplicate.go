@@ -0,0 +1,55 @@
+package env
+
+import (
+	"reflect"
+	"slices"
+)
+
+// DuplicatePolicy controls what NewVar does when a name is declared again.
+// Names are compared after normalisation, so "foo" and "FOO" are the same
+// variable.
+type DuplicatePolicy int
+
+const (
+	// DuplicatePanic panics. It is the default.
+	DuplicatePanic DuplicatePolicy = iota
+	// DuplicateReport records a *DuplicateError, reported by Err and Parse.
+	// The first declaration keeps the name; later ones are read from the
+	// environment, when declared or by Parse, but are not registered.
+	DuplicateReport
+	// DuplicateAllowIdentical returns the existing ConfigVar when the
+	// redeclaration has the same type, default, description, options and
+	// secrecy, e.g. when several plugins share a variable. Other duplicates
+	// are handled as with DuplicateReport.
+	DuplicateAllowIdentical
+)
+
+// DuplicateError is recorded when a variable name is declared more than once.
+type DuplicateError struct {
+	Name string
+}
+
+func (e *DuplicateError) Error() string {
+	return "env: " + e.Name + " already defined"
+}
+
+// SetDuplicatePolicy sets how declaring a name more than once is handled.
+func (e *EnvSet) SetDuplicatePolicy(p DuplicatePolicy) {
+	e.Lock()
+	defer e.Unlock()
+	e.duplicates = p
+}
+
+// SetDuplicatePolicy sets how declaring a name more than once is handled.
+func SetDuplicatePolicy(p DuplicatePolicy) {
+	DefaultEnv.SetDuplicatePolicy(p)
+}
+
+// identical reports whether other declares the same variable as v.
+func (v *ConfigVar) identical(other *ConfigVar) bool {
+	return reflect.TypeOf(v.Value) == reflect.TypeOf(other.Value) &&
+		v.Default == other.Default &&
+		v.Description == other.Description &&
+		v.Secret == other.Secret &&
+		slices.Equal(v.Options, other.Options)
+}
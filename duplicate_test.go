@@ -0,0 +1,96 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicatePanic(t *testing.T) {
+	set := NewEnvSet("test")
+	set.String("foo", "", "")
+	assert.PanicsWithValue(t, "env: FOO already defined.", func() { set.String("FOO", "", "") })
+}
+
+func TestTryNewVar(t *testing.T) {
+	set := NewEnvSet("test")
+	v, err := set.TryNewVar(newStringValue("a"), "foo", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "FOO", v.Name)
+
+	v, err = set.TryNewVar(newStringValue("a"), "Foo", "")
+	assert.Nil(t, v)
+	assert.Equal(t, &DuplicateError{Name: "FOO"}, err)
+	assert.NoError(t, set.Err())
+}
+
+func TestDuplicateReport(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetDuplicatePolicy(DuplicateReport)
+	t.Setenv("FOO", "1")
+	assert.Equal(t, "1", set.String("foo", "a", ""))
+	assert.Equal(t, 1, set.Int("FOO", 0, ""))
+	assert.EqualError(t, set.Err(), "env: FOO already defined")
+	assert.Equal(t, "1", set.Var("foo").Get())
+	assert.Len(t, set.Vars(), 1)
+}
+
+func TestDuplicateAllowIdentical(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetDuplicatePolicy(DuplicateAllowIdentical)
+	set.Int("plugin_timeout", 5, "timeout")
+	first := set.Var("plugin_timeout")
+	assert.Equal(t, 5, set.Int("PLUGIN_TIMEOUT", 5, "timeout"))
+	assert.Same(t, first, set.Var("plugin_timeout"))
+	assert.NoError(t, set.Err())
+
+	v, err := set.TryNewVar(newIntValue(5), "plugin_timeout", "timeout")
+	assert.NoError(t, err)
+	assert.Same(t, first, v)
+
+	set.Int("plugin_timeout", 10, "timeout")
+	set.Uint("plugin_timeout", 5, "timeout")
+	set.Int("plugin_timeout", 5, "other")
+	assert.Len(t, set.Err().(interface{ Unwrap() []error }).Unwrap(), 3)
+}
+
+func TestDuplicateReportParse(t *testing.T) {
+	for _, deferred := range []bool{true, false} {
+		set := NewEnvSet("test")
+		set.SetDeferred(deferred)
+		set.SetDuplicatePolicy(DuplicateReport)
+		set.String("a", "", "")
+		set.String("A", "", "")
+		assert.EqualError(t, set.Err(), "env: A already defined")
+
+		// Parse re-reads the environment but keeps declaration errors.
+		assert.EqualError(t, set.Parse(), "env: A already defined")
+		assert.EqualError(t, set.Parse(), "env: A already defined")
+		assert.EqualError(t, set.Err(), "env: A already defined")
+	}
+}
+
+func TestDuplicateReportDeferredGet(t *testing.T) {
+	t.Setenv("DUP_PORT", "8080")
+	set := NewEnvSet("test")
+	set.SetDeferred(true)
+	set.SetDuplicatePolicy(DuplicateAllowIdentical)
+	set.Int("dup_port", 80, "port")
+	dup := set.NewVar(newUintValue(80), "dup_port", "port")
+	assert.Error(t, set.Parse())
+
+	// The rejected declaration is read by Parse, so Get does not panic.
+	assert.Equal(t, uint(8080), dup.Get())
+}
+
+func TestDuplicateAllowIdenticalOptions(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetDuplicatePolicy(DuplicateAllowIdentical)
+	set.StringOption("mode", "a", []string{"a", "b"}, "")
+	set.StringOption("mode", "a", []string{"a", "b"}, "")
+	assert.NoError(t, set.Err())
+
+	set.StringOption("mode", "a", []string{"a", "c"}, "")
+	assert.EqualError(t, set.Err(), "env: MODE already defined")
+	assert.Equal(t, []string{"a", "b"}, set.Var("mode").Options)
+}
@@ -1,35 +1,91 @@
 package env
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
 type EnvSet struct {
+	// Usage is the function called to print the documentation of the
+	// variables. It may be replaced; by default it prints an ENVIRONMENT
+	// section to Output, which is also done when it is nil.
+	Usage func()
+
 	sync.Mutex
 	name string
 	vars map[string]*ConfigVar
+	errs []error // from reading the environment; reset by Parse
+
+	declErrs []error      // from declaring variables, such as duplicates
+	rejected []*ConfigVar // duplicates that are read but not in vars
+
+	boolWords  map[string]bool
+	aliases    map[string][]string
+	deprecated map[string]string
+	logger     Logger
+	prefix     string
+	strict     bool
+	deferred   bool
+	parsed     bool
+	source     Source
+	duplicates DuplicatePolicy
+	mapper     NameMapper
+	logOptions LogOptions
+	required   map[string]bool
+	out        io.Writer
+	parent     *EnvSet // set by Child; never changes
+}
+
+// ParseError is recorded when the value of an environment variable cannot be
+// parsed into its ConfigVar.
+type ParseError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("env: invalid value %q for %s: %v", e.Value, e.Name, e.Err)
 }
 
+func (e *ParseError) Unwrap() error { return e.Err }
+
 // ConfigVar represents a value from the environment.
+// Value may be replaced while the EnvSet is parsed; use Get and String to read
+// it from several goroutines.
 type ConfigVar struct {
 	Name        string
 	Description string
 	Value       Value  // value as set
 	Default     string // default value (as text); for description message
 	Secret      bool
+	Options     []string // allowed values (as text); for description message
+	Required    bool     // the variable must be set
+	Aliases     []string // deprecated names read when Name is not set
+	Deprecated  bool
+	ReplacedBy  string // name to use instead of a deprecated variable
+
+	mu       sync.RWMutex // guards Value, resolved and origin
+	reset    func()       // restores Value to its default
+	resolved bool         // Value has been read from the environment
+	origin   string       // environment variable Value was read from
 }
 
 // String retrieves a environment variable by name and parses it to a string
 // defaultVal will be returned if the variable is not found.
 func (e *EnvSet) String(name string, defaultVal string, description string) string {
 	v := e.NewVar(newStringValue(defaultVal), name, description)
-	return v.Value.Get().(string)
+	return v.value().(string)
 }
 
 // String retrieves a environment variable by name and parses it to a string
@@ -39,19 +95,16 @@ func String(name string, defaultVal string, description string) string {
 }
 
 // StringOption like String except the value must be in options.
-// defaultVal will be returned if the variable is not found or is not a valid option.
+// defaultVal will be returned if the variable is not found or is not a valid option,
+// which is reported by Err.
 func (e *EnvSet) StringOption(name string, defaultVal string, options []string, description string) string {
-	v := e.NewVar(newStringValue(defaultVal), name, description)
-	for _, option := range options {
-		if option == v.Value.Get().(string) {
-			return v.Value.Get().(string)
-		}
-	}
-	return defaultVal
+	v, _ := e.newVar(newOptionValue(newStringValue(defaultVal), options), name, description, optionText(options), false, false)
+	return v.value().(string)
 }
 
 // StringOption like String except the value must be in options.
-// defaultVal will be returned if the variable is not found or is not a valid option.
+// defaultVal will be returned if the variable is not found or is not a valid option,
+// which is reported by Err.
 func StringOption(name string, defaultVal string, options []string, description string) string {
 	return DefaultEnv.StringOption(name, defaultVal, options, description)
 }
@@ -60,7 +113,7 @@ func StringOption(name string, defaultVal string, options []string, description
 // defaultVal will be returned if the variable is not found.
 func (e *EnvSet) StringList(name string, defaultVal []string, description string) []string {
 	v := e.NewVar(newStringListValue(defaultVal), name, description)
-	return v.Value.Get().([]string)
+	return v.value().([]string)
 }
 
 // StringList returns a slice of strings from a comma-sep value
@@ -69,12 +122,38 @@ func StringList(name string, defaultVal []string, description string) []string {
 	return DefaultEnv.StringList(name, defaultVal, description)
 }
 
+// StringMap retrieves a environment variable by name and parses it to a map of
+// strings from comma-sep key:value pairs.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) StringMap(name string, defaultVal map[string]string, description string) map[string]string {
+	return e.StringMapSep(name, defaultVal, defaultPairSep, defaultKVSep, description)
+}
+
+// StringMap retrieves a environment variable by name and parses it to a map of
+// strings from comma-sep key:value pairs.
+// defaultVal will be returned if the variable is not found.
+func StringMap(name string, defaultVal map[string]string, description string) map[string]string {
+	return DefaultEnv.StringMap(name, defaultVal, description)
+}
+
+// StringMapSep like StringMap except pairs are separated by pairSep and keys
+// are separated from values by kvSep.
+func (e *EnvSet) StringMapSep(name string, defaultVal map[string]string, pairSep, kvSep string, description string) map[string]string {
+	v := e.NewVar(newStringMapValue(defaultVal, pairSep, kvSep), name, description)
+	return v.value().(map[string]string)
+}
+
+// StringMapSep like StringMap except pairs are separated by pairSep and keys
+// are separated from values by kvSep.
+func StringMapSep(name string, defaultVal map[string]string, pairSep, kvSep string, description string) map[string]string {
+	return DefaultEnv.StringMapSep(name, defaultVal, pairSep, kvSep, description)
+}
+
 // Secret retrieves a environment variable by name and parses it to a secret string
 // defaultVal will be returned if the variable is not found.
 func (e *EnvSet) Secret(name string, description string) string {
-	v := e.NewVar(newSecretValue(""), name, description)
-	v.Secret = true
-	return v.Value.Get().(string)
+	v, _ := e.newVar(newSecretValue(""), name, description, nil, true, false)
+	return v.value().(string)
 }
 
 // Secret retrieves a environment variable by name and parses it to a secret string
@@ -84,23 +163,61 @@ func Secret(name string, description string) string {
 }
 
 // Bool retrieves a environment variable by name and parses it to a bool
+// from words such as true/false, yes/no, on/off or enabled/disabled.
 // defaultVal will be returned if the variable is not found.
 func (e *EnvSet) Bool(name string, defaultVal bool, description string) bool {
-	v := e.NewVar(newBoolValue(defaultVal), name, description)
-	return v.Value.Get().(bool)
+	e.Lock()
+	words := e.boolWords
+	e.Unlock()
+	if words == nil {
+		words = defaultBoolWords
+	}
+	v := e.NewVar(newBoolValue(defaultVal, words), name, description)
+	return v.value().(bool)
 }
 
 // Bool retrieves a environment variable by name and parses it to a bool
+// from words such as true/false, yes/no, on/off or enabled/disabled.
 // defaultVal will be returned if the variable is not found.
 func Bool(name string, defaultVal bool, description string) bool {
 	return DefaultEnv.Bool(name, defaultVal, description)
 }
 
+// BoolWords replaces the words Bool accepts for true and false. Words are
+// matched case-insensitively and any other value is reported as an error.
+// Only variables declared after the call are affected.
+func (e *EnvSet) BoolWords(trueWords, falseWords []string) {
+	words := boolWords(trueWords, falseWords)
+	e.Lock()
+	defer e.Unlock()
+	e.boolWords = words
+}
+
+// BoolWords replaces the words Bool accepts for true and false. Words are
+// matched case-insensitively and any other value is reported as an error.
+// Only variables declared after the call are affected.
+func BoolWords(trueWords, falseWords []string) {
+	DefaultEnv.BoolWords(trueWords, falseWords)
+}
+
+// Float32 retrieves a environment variable by name and parses it to a float32
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Float32(name string, defaultVal float32, description string) float32 {
+	v := e.NewVar(newFloat32Value(defaultVal), name, description)
+	return v.value().(float32)
+}
+
+// Float32 retrieves a environment variable by name and parses it to a float32
+// defaultVal will be returned if the variable is not found.
+func Float32(name string, defaultVal float32, description string) float32 {
+	return DefaultEnv.Float32(name, defaultVal, description)
+}
+
 // Float64 retrieves a environment variable by name and parses it to a float64
 // defaultVal will be returned if the variable is not found.
 func (e *EnvSet) Float64(name string, defaultVal float64, description string) float64 {
 	v := e.NewVar(newFloat64Value(defaultVal), name, description)
-	return v.Value.Get().(float64)
+	return v.value().(float64)
 }
 
 // Float64 retrieves a environment variable by name and parses it to a float64
@@ -110,22 +227,45 @@ func Float64(name string, defaultVal float64, description string) float64 {
 }
 
 // Float64Option like Float64 except the value must be in options.
-// defaultVal will be returned if the variable is not found or is not a valid option.
+// defaultVal will be returned if the variable is not found or is not a valid option,
+// which is reported by Err.
 func (e *EnvSet) Float64Option(name string, defaultVal float64, options []float64, description string) float64 {
-	v := e.NewVar(newFloat64Value(defaultVal), name, description)
-	for _, option := range options {
-		if option == v.Value.Get().(float64) {
-			return v.Value.Get().(float64)
-		}
-	}
-	return defaultVal
+	v, _ := e.newVar(newOptionValue(newFloat64Value(defaultVal), options), name, description, optionText(options), false, false)
+	return v.value().(float64)
+}
+
+// Float64Map retrieves a environment variable by name and parses it to a map of
+// float64s from comma-sep key:value pairs.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Float64Map(name string, defaultVal map[string]float64, description string) map[string]float64 {
+	return e.Float64MapSep(name, defaultVal, defaultPairSep, defaultKVSep, description)
+}
+
+// Float64Map retrieves a environment variable by name and parses it to a map of
+// float64s from comma-sep key:value pairs.
+// defaultVal will be returned if the variable is not found.
+func Float64Map(name string, defaultVal map[string]float64, description string) map[string]float64 {
+	return DefaultEnv.Float64Map(name, defaultVal, description)
+}
+
+// Float64MapSep like Float64Map except pairs are separated by pairSep and keys
+// are separated from values by kvSep.
+func (e *EnvSet) Float64MapSep(name string, defaultVal map[string]float64, pairSep, kvSep string, description string) map[string]float64 {
+	v := e.NewVar(newFloat64MapValue(defaultVal, pairSep, kvSep), name, description)
+	return v.value().(map[string]float64)
+}
+
+// Float64MapSep like Float64Map except pairs are separated by pairSep and keys
+// are separated from values by kvSep.
+func Float64MapSep(name string, defaultVal map[string]float64, pairSep, kvSep string, description string) map[string]float64 {
+	return DefaultEnv.Float64MapSep(name, defaultVal, pairSep, kvSep, description)
 }
 
 // Int retrieves a environment variable by name and parses it to a int
 // defaultVal will be returned if the variable is not found.
 func (e *EnvSet) Int(name string, defaultVal int, description string) int {
 	v := e.NewVar(newIntValue(defaultVal), name, description)
-	return v.Value.Get().(int)
+	return v.value().(int)
 }
 
 // Int retrieves a environment variable by name and parses it to a int
@@ -135,22 +275,84 @@ func Int(name string, defaultVal int, description string) int {
 }
 
 // IntOption like Int except the value must be in options.
-// defaultVal will be returned if the variable is not found or is not a valid option.
+// defaultVal will be returned if the variable is not found or is not a valid option,
+// which is reported by Err.
 func (e *EnvSet) IntOption(name string, defaultVal int, options []int, description string) int {
-	v := e.NewVar(newIntValue(defaultVal), name, description)
-	for _, option := range options {
-		if option == v.Value.Get().(int) {
-			return v.Value.Get().(int)
-		}
-	}
-	return defaultVal
+	v, _ := e.newVar(newOptionValue(newIntValue(defaultVal), options), name, description, optionText(options), false, false)
+	return v.value().(int)
+}
+
+// IntMap retrieves a environment variable by name and parses it to a map of
+// ints from comma-sep key:value pairs.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) IntMap(name string, defaultVal map[string]int, description string) map[string]int {
+	return e.IntMapSep(name, defaultVal, defaultPairSep, defaultKVSep, description)
+}
+
+// IntMap retrieves a environment variable by name and parses it to a map of
+// ints from comma-sep key:value pairs.
+// defaultVal will be returned if the variable is not found.
+func IntMap(name string, defaultVal map[string]int, description string) map[string]int {
+	return DefaultEnv.IntMap(name, defaultVal, description)
+}
+
+// IntMapSep like IntMap except pairs are separated by pairSep and keys
+// are separated from values by kvSep.
+func (e *EnvSet) IntMapSep(name string, defaultVal map[string]int, pairSep, kvSep string, description string) map[string]int {
+	v := e.NewVar(newIntMapValue(defaultVal, pairSep, kvSep), name, description)
+	return v.value().(map[string]int)
+}
+
+// IntMapSep like IntMap except pairs are separated by pairSep and keys
+// are separated from values by kvSep.
+func IntMapSep(name string, defaultVal map[string]int, pairSep, kvSep string, description string) map[string]int {
+	return DefaultEnv.IntMapSep(name, defaultVal, pairSep, kvSep, description)
+}
+
+// Int8 retrieves a environment variable by name and parses it to a int8
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Int8(name string, defaultVal int8, description string) int8 {
+	v := e.NewVar(newInt8Value(defaultVal), name, description)
+	return v.value().(int8)
+}
+
+// Int8 retrieves a environment variable by name and parses it to a int8
+// defaultVal will be returned if the variable is not found.
+func Int8(name string, defaultVal int8, description string) int8 {
+	return DefaultEnv.Int8(name, defaultVal, description)
+}
+
+// Int16 retrieves a environment variable by name and parses it to a int16
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Int16(name string, defaultVal int16, description string) int16 {
+	v := e.NewVar(newInt16Value(defaultVal), name, description)
+	return v.value().(int16)
+}
+
+// Int16 retrieves a environment variable by name and parses it to a int16
+// defaultVal will be returned if the variable is not found.
+func Int16(name string, defaultVal int16, description string) int16 {
+	return DefaultEnv.Int16(name, defaultVal, description)
+}
+
+// Int32 retrieves a environment variable by name and parses it to a int32
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Int32(name string, defaultVal int32, description string) int32 {
+	v := e.NewVar(newInt32Value(defaultVal), name, description)
+	return v.value().(int32)
+}
+
+// Int32 retrieves a environment variable by name and parses it to a int32
+// defaultVal will be returned if the variable is not found.
+func Int32(name string, defaultVal int32, description string) int32 {
+	return DefaultEnv.Int32(name, defaultVal, description)
 }
 
 // Int64 retrieves a environment variable by name and parses it to a int64
 // defaultVal will be returned if the variable is not found.
 func (e *EnvSet) Int64(name string, defaultVal int64, description string) int64 {
 	v := e.NewVar(newInt64Value(defaultVal), name, description)
-	return v.Value.Get().(int64)
+	return v.value().(int64)
 }
 
 // Int64 retrieves a environment variable by name and parses it to a int64
@@ -160,22 +362,18 @@ func Int64(name string, defaultVal int64, description string) int64 {
 }
 
 // Int64Option like Int64 except the value must be in options.
-// defaultVal will be returned if the variable is not found or is not a valid option.
+// defaultVal will be returned if the variable is not found or is not a valid option,
+// which is reported by Err.
 func (e *EnvSet) Int64Option(name string, defaultVal int64, options []int64, description string) int64 {
-	v := e.NewVar(newInt64Value(defaultVal), name, description)
-	for _, option := range options {
-		if option == v.Value.Get().(int64) {
-			return v.Value.Get().(int64)
-		}
-	}
-	return defaultVal
+	v, _ := e.newVar(newOptionValue(newInt64Value(defaultVal), options), name, description, optionText(options), false, false)
+	return v.value().(int64)
 }
 
 // Uint retrieves a environment variable by name and parses it to a uint
 // defaultVal will be returned if the variable is not found.
 func (e *EnvSet) Uint(name string, defaultVal uint, description string) uint {
 	v := e.NewVar(newUintValue(defaultVal), name, description)
-	return v.Value.Get().(uint)
+	return v.value().(uint)
 }
 
 // Uint retrieves a environment variable by name and parses it to a uint
@@ -185,22 +383,57 @@ func Uint(name string, defaultVal uint, description string) uint {
 }
 
 // UintOption like Uint except the value must be in options.
-// defaultVal will be returned if the variable is not found or is not a valid option.
+// defaultVal will be returned if the variable is not found or is not a valid option,
+// which is reported by Err.
 func (e *EnvSet) UintOption(name string, defaultVal uint, options []uint, description string) uint {
-	v := e.NewVar(newUintValue(defaultVal), name, description)
-	for _, option := range options {
-		if option == v.Value.Get().(uint) {
-			return v.Value.Get().(uint)
-		}
-	}
-	return defaultVal
+	v, _ := e.newVar(newOptionValue(newUintValue(defaultVal), options), name, description, optionText(options), false, false)
+	return v.value().(uint)
+}
+
+// Uint8 retrieves a environment variable by name and parses it to a uint8
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Uint8(name string, defaultVal uint8, description string) uint8 {
+	v := e.NewVar(newUint8Value(defaultVal), name, description)
+	return v.value().(uint8)
+}
+
+// Uint8 retrieves a environment variable by name and parses it to a uint8
+// defaultVal will be returned if the variable is not found.
+func Uint8(name string, defaultVal uint8, description string) uint8 {
+	return DefaultEnv.Uint8(name, defaultVal, description)
+}
+
+// Uint16 retrieves a environment variable by name and parses it to a uint16
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Uint16(name string, defaultVal uint16, description string) uint16 {
+	v := e.NewVar(newUint16Value(defaultVal), name, description)
+	return v.value().(uint16)
+}
+
+// Uint16 retrieves a environment variable by name and parses it to a uint16
+// defaultVal will be returned if the variable is not found.
+func Uint16(name string, defaultVal uint16, description string) uint16 {
+	return DefaultEnv.Uint16(name, defaultVal, description)
+}
+
+// Uint32 retrieves a environment variable by name and parses it to a uint32
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Uint32(name string, defaultVal uint32, description string) uint32 {
+	v := e.NewVar(newUint32Value(defaultVal), name, description)
+	return v.value().(uint32)
+}
+
+// Uint32 retrieves a environment variable by name and parses it to a uint32
+// defaultVal will be returned if the variable is not found.
+func Uint32(name string, defaultVal uint32, description string) uint32 {
+	return DefaultEnv.Uint32(name, defaultVal, description)
 }
 
 // Uint64 retrieves a environment variable by name and parses it to a uint64
 // defaultVal will be returned if the variable is not found.
 func (e *EnvSet) Uint64(name string, defaultVal uint64, description string) uint64 {
 	v := e.NewVar(newUint64Value(defaultVal), name, description)
-	return v.Value.Get().(uint64)
+	return v.value().(uint64)
 }
 
 // Uint64 retrieves a environment variable by name and parses it to a uint64
@@ -210,31 +443,170 @@ func Uint64(name string, defaultVal uint64, description string) uint64 {
 }
 
 // Uint64Option like Uint64 except the value must be in options.
-// defaultVal will be returned if the variable is not found or is not a valid option.
+// defaultVal will be returned if the variable is not found or is not a valid option,
+// which is reported by Err.
 func (e *EnvSet) Uint64Option(name string, defaultVal uint64, options []uint64, description string) uint64 {
-	v := e.NewVar(newUint64Value(defaultVal), name, description)
-	for _, option := range options {
-		if option == v.Value.Get().(uint64) {
-			return v.Value.Get().(uint64)
-		}
-	}
-	return defaultVal
+	v, _ := e.newVar(newOptionValue(newUint64Value(defaultVal), options), name, description, optionText(options), false, false)
+	return v.value().(uint64)
 }
 
 func (e *EnvSet) Duration(name string, defaultVal time.Duration, description string) time.Duration {
 	v := e.NewVar(newDurationValue(defaultVal), name, description)
-	return v.Value.Get().(time.Duration)
+	return v.value().(time.Duration)
 }
 
 func Duration(name string, defaultVal time.Duration, description string) time.Duration {
 	return DefaultEnv.Duration(name, defaultVal, description)
 }
 
+// Time retrieves a environment variable by name and parses it to a time.Time
+// in RFC 3339 format.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Time(name string, defaultVal time.Time, description string) time.Time {
+	return e.TimeLayout(name, defaultVal, []string{time.RFC3339}, description)
+}
+
+// Time retrieves a environment variable by name and parses it to a time.Time
+// in RFC 3339 format.
+// defaultVal will be returned if the variable is not found.
+func Time(name string, defaultVal time.Time, description string) time.Time {
+	return DefaultEnv.Time(name, defaultVal, description)
+}
+
+// TimeLayout like Time except the value is parsed with the first of layouts
+// that matches. The first layout is used to print the value.
+func (e *EnvSet) TimeLayout(name string, defaultVal time.Time, layouts []string, description string) time.Time {
+	if len(layouts) == 0 {
+		panic("env: " + name + " has no time layouts.")
+	}
+	v := e.NewVar(newTimeValue(defaultVal, layouts), name, description)
+	return v.value().(time.Time)
+}
+
+// TimeLayout like Time except the value is parsed with the first of layouts
+// that matches. The first layout is used to print the value.
+func TimeLayout(name string, defaultVal time.Time, layouts []string, description string) time.Time {
+	return DefaultEnv.TimeLayout(name, defaultVal, layouts, description)
+}
+
+// Location retrieves a environment variable by name and loads the
+// *time.Location with that IANA name, e.g. Europe/Berlin.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Location(name string, defaultVal *time.Location, description string) *time.Location {
+	v := e.NewVar(newLocationValue(defaultVal), name, description)
+	return v.value().(*time.Location)
+}
+
+// Location retrieves a environment variable by name and loads the
+// *time.Location with that IANA name, e.g. Europe/Berlin.
+// defaultVal will be returned if the variable is not found.
+func Location(name string, defaultVal *time.Location, description string) *time.Location {
+	return DefaultEnv.Location(name, defaultVal, description)
+}
+
+// Cron retrieves a environment variable by name and parses it to a *Schedule
+// from a cron expression.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Cron(name string, defaultVal *Schedule, description string) *Schedule {
+	v := e.NewVar(newScheduleValue(defaultVal), name, description)
+	return v.value().(*Schedule)
+}
+
+// Cron retrieves a environment variable by name and parses it to a *Schedule
+// from a cron expression.
+// defaultVal will be returned if the variable is not found.
+func Cron(name string, defaultVal *Schedule, description string) *Schedule {
+	return DefaultEnv.Cron(name, defaultVal, description)
+}
+
+// Size retrieves a environment variable by name and parses it to a ByteSize
+// from a size such as 64MiB or 1.5GB.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Size(name string, defaultVal ByteSize, description string) ByteSize {
+	return e.SizeRange(name, defaultVal, 0, math.MaxUint64, description)
+}
+
+// Size retrieves a environment variable by name and parses it to a ByteSize
+// from a size such as 64MiB or 1.5GB.
+// defaultVal will be returned if the variable is not found.
+func Size(name string, defaultVal ByteSize, description string) ByteSize {
+	return DefaultEnv.Size(name, defaultVal, description)
+}
+
+// SizeRange like Size except the value must be between min and max inclusive.
+// defaultVal will be returned if the variable is not found or is out of range.
+func (e *EnvSet) SizeRange(name string, defaultVal, min, max ByteSize, description string) ByteSize {
+	v := e.NewVar(newByteSizeValue(defaultVal, min, max), name, description)
+	return v.value().(ByteSize)
+}
+
+// SizeRange like Size except the value must be between min and max inclusive.
+// defaultVal will be returned if the variable is not found or is out of range.
+func SizeRange(name string, defaultVal, min, max ByteSize, description string) ByteSize {
+	return DefaultEnv.SizeRange(name, defaultVal, min, max, description)
+}
+
+// Percentage retrieves a environment variable by name and parses it to a Percent
+// between 0% and 100%.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Percentage(name string, defaultVal Percent, description string) Percent {
+	return e.PercentageRange(name, defaultVal, 0, 100, description)
+}
+
+// Percentage retrieves a environment variable by name and parses it to a Percent
+// between 0% and 100%.
+// defaultVal will be returned if the variable is not found.
+func Percentage(name string, defaultVal Percent, description string) Percent {
+	return DefaultEnv.Percentage(name, defaultVal, description)
+}
+
+// PercentageRange like Percentage except the value must be between min and max inclusive.
+// defaultVal will be returned if the variable is not found or is out of range.
+func (e *EnvSet) PercentageRange(name string, defaultVal, min, max Percent, description string) Percent {
+	v := e.NewVar(newPercentValue(defaultVal, min, max), name, description)
+	return v.value().(Percent)
+}
+
+// PercentageRange like Percentage except the value must be between min and max inclusive.
+// defaultVal will be returned if the variable is not found or is out of range.
+func PercentageRange(name string, defaultVal, min, max Percent, description string) Percent {
+	return DefaultEnv.PercentageRange(name, defaultVal, min, max, description)
+}
+
+// Frequency retrieves a environment variable by name and parses it to a Rate
+// from a value such as 100/s or 5/min.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) Frequency(name string, defaultVal Rate, description string) Rate {
+	return e.FrequencyRange(name, defaultVal, Rate{}, Rate{}, description)
+}
+
+// Frequency retrieves a environment variable by name and parses it to a Rate
+// from a value such as 100/s or 5/min.
+// defaultVal will be returned if the variable is not found.
+func Frequency(name string, defaultVal Rate, description string) Rate {
+	return DefaultEnv.Frequency(name, defaultVal, description)
+}
+
+// FrequencyRange like Frequency except the value must be between min and max inclusive.
+// A zero max means there is no upper limit.
+// defaultVal will be returned if the variable is not found or is out of range.
+func (e *EnvSet) FrequencyRange(name string, defaultVal, min, max Rate, description string) Rate {
+	v := e.NewVar(newRateValue(defaultVal, min, max), name, description)
+	return v.value().(Rate)
+}
+
+// FrequencyRange like Frequency except the value must be between min and max inclusive.
+// A zero max means there is no upper limit.
+// defaultVal will be returned if the variable is not found or is out of range.
+func FrequencyRange(name string, defaultVal, min, max Rate, description string) Rate {
+	return DefaultEnv.FrequencyRange(name, defaultVal, min, max, description)
+}
+
 // IP retrieves a environment variable by name and parses it to a net.IP
 // defaultVal will be returned if the variable is not found.
 func (e *EnvSet) IP(name string, defaultVal net.IP, description string) net.IP {
 	v := e.NewVar(newIPValue(defaultVal), name, description)
-	return v.Value.Get().(net.IP)
+	return v.value().(net.IP)
 }
 
 // IP retrieves a environment variable by name and parses it to a net.IP
@@ -243,42 +615,205 @@ func IP(name string, defaultVal net.IP, description string) net.IP {
 	return DefaultEnv.IP(name, defaultVal, description)
 }
 
+// IPNet retrieves a environment variable by name and parses it to a *net.IPNet
+// from CIDR notation such as 10.0.0.0/8.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) IPNet(name string, defaultVal *net.IPNet, description string) *net.IPNet {
+	v := e.NewVar(newIPNetValue(defaultVal), name, description)
+	return v.value().(*net.IPNet)
+}
+
+// IPNet retrieves a environment variable by name and parses it to a *net.IPNet
+// from CIDR notation such as 10.0.0.0/8.
+// defaultVal will be returned if the variable is not found.
+func IPNet(name string, defaultVal *net.IPNet, description string) *net.IPNet {
+	return DefaultEnv.IPNet(name, defaultVal, description)
+}
+
+// IPAddr retrieves a environment variable by name and parses it to a netip.Addr
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) IPAddr(name string, defaultVal netip.Addr, description string) netip.Addr {
+	v := e.NewVar(newIPAddrValue(defaultVal), name, description)
+	return v.value().(netip.Addr)
+}
+
+// IPAddr retrieves a environment variable by name and parses it to a netip.Addr
+// defaultVal will be returned if the variable is not found.
+func IPAddr(name string, defaultVal netip.Addr, description string) netip.Addr {
+	return DefaultEnv.IPAddr(name, defaultVal, description)
+}
+
+// IPPrefix retrieves a environment variable by name and parses it to a netip.Prefix
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) IPPrefix(name string, defaultVal netip.Prefix, description string) netip.Prefix {
+	v := e.NewVar(newIPPrefixValue(defaultVal), name, description)
+	return v.value().(netip.Prefix)
+}
+
+// IPPrefix retrieves a environment variable by name and parses it to a netip.Prefix
+// defaultVal will be returned if the variable is not found.
+func IPPrefix(name string, defaultVal netip.Prefix, description string) netip.Prefix {
+	return DefaultEnv.IPPrefix(name, defaultVal, description)
+}
+
+// HostPort retrieves a environment variable by name and parses it to a host:port
+// string. defaultPort is used when the value has no port.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) HostPort(name string, defaultVal string, defaultPort string, description string) string {
+	v := e.NewVar(newHostPortValue(defaultVal, defaultPort), name, description)
+	return v.value().(string)
+}
+
+// HostPort retrieves a environment variable by name and parses it to a host:port
+// string. defaultPort is used when the value has no port.
+// defaultVal will be returned if the variable is not found.
+func HostPort(name string, defaultVal string, defaultPort string, description string) string {
+	return DefaultEnv.HostPort(name, defaultVal, defaultPort, description)
+}
+
+// TCPAddr retrieves a environment variable by name as a host:port address.
+// The returned func resolves the address the first time it is called and
+// returns the same result after that.
+// defaultVal will be used if the variable is not found.
+func (e *EnvSet) TCPAddr(name string, defaultVal string, description string) func() (*net.TCPAddr, error) {
+	v := e.NewVar(newTCPAddrValue(defaultVal), name, description)
+	return v.value().(func() (*net.TCPAddr, error))
+}
+
+// TCPAddr retrieves a environment variable by name as a host:port address.
+// The returned func resolves the address the first time it is called and
+// returns the same result after that.
+// defaultVal will be used if the variable is not found.
+func TCPAddr(name string, defaultVal string, description string) func() (*net.TCPAddr, error) {
+	return DefaultEnv.TCPAddr(name, defaultVal, description)
+}
+
+// UDPAddr retrieves a environment variable by name as a host:port address.
+// The returned func resolves the address the first time it is called and
+// returns the same result after that.
+// defaultVal will be used if the variable is not found.
+func (e *EnvSet) UDPAddr(name string, defaultVal string, description string) func() (*net.UDPAddr, error) {
+	v := e.NewVar(newUDPAddrValue(defaultVal), name, description)
+	return v.value().(func() (*net.UDPAddr, error))
+}
+
+// UDPAddr retrieves a environment variable by name as a host:port address.
+// The returned func resolves the address the first time it is called and
+// returns the same result after that.
+// defaultVal will be used if the variable is not found.
+func UDPAddr(name string, defaultVal string, description string) func() (*net.UDPAddr, error) {
+	return DefaultEnv.UDPAddr(name, defaultVal, description)
+}
+
+// URL retrieves a environment variable by name and parses it to an absolute *url.URL.
+// If schemes is not empty the URL's scheme must be one of them.
+// defaultVal will be returned if the variable is not found.
+func (e *EnvSet) URL(name string, defaultVal *url.URL, schemes []string, description string) *url.URL {
+	v := e.NewVar(newURLValue(defaultVal, schemes), name, description)
+	return v.value().(*url.URL)
+}
+
+// URL retrieves a environment variable by name and parses it to an absolute *url.URL.
+// If schemes is not empty the URL's scheme must be one of them.
+// defaultVal will be returned if the variable is not found.
+func URL(name string, defaultVal *url.URL, schemes []string, description string) *url.URL {
+	return DefaultEnv.URL(name, defaultVal, schemes, description)
+}
+
+// VisitAll calls fn for each ConfigVar in lexicographical order of name.
+// fn is called without the EnvSet locked so it may declare variables.
 func (e *EnvSet) VisitAll(fn func(*ConfigVar)) {
-	for _, cfg := range e.vars {
+	for _, cfg := range e.sortedVars() {
 		fn(cfg)
 	}
 }
 
+// VisitAll calls fn for each ConfigVar in lexicographical order of name.
+// fn is called without the EnvSet locked so it may declare variables.
 func VisitAll(fn func(*ConfigVar)) {
 	DefaultEnv.VisitAll(fn)
 }
 
 // NewVar retrieves a variable from the environment that is of type Value.
+// Declaring a name twice panics unless the duplicate policy says otherwise;
+// see SetDuplicatePolicy.
 func (e *EnvSet) NewVar(value Value, name string, description string) *ConfigVar {
+	v, _ := e.newVar(value, name, description, nil, false, false)
+	return v
+}
+
+// TryNewVar is like NewVar except a duplicate name is returned as a
+// *DuplicateError instead of being handled by the duplicate policy.
+// Identical redeclarations are still allowed by DuplicateAllowIdentical.
+func (e *EnvSet) TryNewVar(value Value, name string, description string) (*ConfigVar, error) {
+	return e.newVar(value, name, description, nil, false, true)
+}
+
+// TryNewVar is like NewVar except a duplicate name is returned as a
+// *DuplicateError instead of being handled by the duplicate policy.
+// Identical redeclarations are still allowed by DuplicateAllowIdentical.
+func TryNewVar(value Value, name string, description string) (*ConfigVar, error) {
+	return DefaultEnv.TryNewVar(value, name, description)
+}
+
+// newVar declares a variable, with options as its allowed values when it has
+// any. Every field of the ConfigVar is set before it is published in e.vars.
+func (e *EnvSet) newVar(value Value, name string, description string, options []string, secret, try bool) (*ConfigVar, error) {
+	var warnings []string
+	var logger Logger
+	defer func() {
+		for _, w := range warnings {
+			logger.Printf("%s", w)
+		}
+	}()
+
 	e.Lock()
 	defer e.Unlock()
 	envVar := &ConfigVar{
-		Name:        strings.ToUpper(name),
+		Name:        e.mapName(name),
 		Description: description,
 		Value:       value,
 		Default:     value.String(),
+		Secret:      secret,
+		Options:     options,
 	}
-	_, defined := e.vars[name]
+	existing, defined := e.vars[envVar.Name]
 	if defined {
-		panic("env: " + name + " already defined.")
+		if e.duplicates == DuplicateAllowIdentical && existing.identical(envVar) {
+			return existing, nil
+		}
+		err := &DuplicateError{Name: envVar.Name}
+		if try {
+			return nil, err
+		}
+		if e.duplicates == DuplicatePanic {
+			panic(err.Error() + ".")
+		}
+		e.declErrs = append(e.declErrs, err)
 	}
+	envVar.Required = e.required[envVar.Name]
+	envVar.Aliases = e.aliases[envVar.Name]
+	envVar.ReplacedBy, envVar.Deprecated = e.deprecated[envVar.Name]
+	envVar.reset = saveValue(value)
 
 	// This step is part of Parse() in flags pkg.
-	if v := os.Getenv(envVar.Name); v != "" {
-		envVar.Value.Set(v)
+	if !e.deferred || e.parsed {
+		warnings = e.resolve(envVar)
+		logger = e.output()
 	}
 
+	// A rejected duplicate is still read, by Parse too, so its declaration
+	// gets a value of the right type, but the first declaration keeps the name.
+	if defined {
+		e.rejected = append(e.rejected, envVar)
+		return envVar, nil
+	}
 	if e.vars == nil {
 		e.vars = make(map[string]*ConfigVar)
 	}
-	e.vars[name] = envVar
+	e.vars[envVar.Name] = envVar
 
-	return envVar
+	return envVar, nil
 }
 
 // NewVar retrieves a variable from the environment that is of type Value.
@@ -286,61 +821,89 @@ func NewVar(value Value, name string, description string) *ConfigVar {
 	return DefaultEnv.NewVar(value, name, description)
 }
 
-// Var retrieves a ConfigVar by name from the ConfigVar map.
-func (e *EnvSet) Var(name string) *ConfigVar {
+// Err returns the errors encountered while parsing variables from the
+// environment, or nil if every value was parsed. In strict mode unknown
+// variables are errors too.
+func (e *EnvSet) Err() error {
 	e.Lock()
 	defer e.Unlock()
-	if v, ok := e.vars[name]; ok {
-		return v
+	return e.err()
+}
+
+// err is Err for a locked EnvSet.
+func (e *EnvSet) err() error {
+	errs := append(append([]error(nil), e.declErrs...), e.errs...)
+	if e.strict {
+		for _, u := range e.unknown() {
+			errs = append(errs, u)
+		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// Err returns the errors encountered while parsing variables from the
+// environment, or nil if every value was parsed. In strict mode unknown
+// variables are errors too.
+func Err() error {
+	return DefaultEnv.Err()
+}
+
+// Var retrieves a ConfigVar by declared or environment name from the ConfigVar map.
+// It returns nil if there is no such variable.
+func (e *EnvSet) Var(name string) *ConfigVar {
+	v, _ := e.Lookup(name)
+	return v
 }
 
-// Var retrieves a ConfigVar by name from the ConfigVar map.
+// Var retrieves a ConfigVar by declared or environment name from the ConfigVar map.
+// It returns nil if there is no such variable.
 func Var(name string) *ConfigVar {
 	return DefaultEnv.Var(name)
 }
 
-// Vars retrieve all ConfigVars from the ConfigVar map.
+// Vars retrieve all ConfigVars from the ConfigVar map, keyed by Name.
+// Those of a child EnvSet include the variables of its parent it has not
+// overridden.
 func (e *EnvSet) Vars() map[string]*ConfigVar {
+	vars := make(map[string]*ConfigVar)
+	if e.parent != nil {
+		vars = e.parent.Vars()
+	}
 	e.Lock()
 	defer e.Unlock()
-	// TODO: this should return a copy of the map
-	return e.vars
+	for name, v := range e.vars {
+		vars[name] = v
+	}
+	return vars
 }
 
-// Vars retrieve all ConfigVars from the ConfigVar map.
+// Vars retrieve all ConfigVars from the ConfigVar map, keyed by Name.
 func Vars() map[string]*ConfigVar {
 	return DefaultEnv.Vars()
 }
 
-// PrintDefaults prints the default values of all defined ConfigVars.
+// PrintDefaults prints the type, default value and description of all defined
+// ConfigVars, sorted by name, with descriptions wrapped.
 func (e *EnvSet) PrintDefaults(out io.Writer) {
-	e.Lock()
-	defer e.Unlock()
-	// TODO: locking could be removed if this used Vars after copying is done
-	for _, v := range e.vars {
-		env := fmt.Sprintf("%s=%q", v.Name, v.Default)
-		fmt.Fprintf(out, "%-40s # %s\n", env, v.Description)
+	for _, v := range e.sortedVars() {
+		printDefault(out, v)
 	}
 }
 
-// PrintDefaults prints the default values of all defined ConfigVars.
+// PrintDefaults prints the type, default value and description of all defined
+// ConfigVars, sorted by name, with descriptions wrapped.
 func PrintDefaults(out io.Writer) {
 	DefaultEnv.PrintDefaults(out)
 }
 
-// PrintEnv prints the set values of all defined ConfigVars.
+// PrintEnv prints the set values of all defined ConfigVars, sorted by name.
 func (e *EnvSet) PrintEnv(out io.Writer, export, secrets bool) {
-	e.Lock()
-	defer e.Unlock()
-	// TODO: locking could be removed if this used Vars after copying is done
-	for _, v := range e.vars {
+	for _, v := range e.sortedVars() {
 		printVar(out, v, export, secrets)
 	}
 }
 
-// PrintEnv prints the set values of all defined ConfigVars.
+// PrintEnv prints the set values of all defined ConfigVars, sorted by name.
 func PrintEnv(out io.Writer, export, secrets bool) {
 	DefaultEnv.PrintEnv(out, export, secrets)
 }
@@ -349,17 +912,32 @@ func (e *EnvSet) Clear() {
 	e.Lock()
 	defer e.Unlock()
 	e.vars = nil
+	e.errs = nil
+	e.declErrs = nil
+	e.rejected = nil
+	e.parsed = false
 }
 
 func Clear() {
 	DefaultEnv.Clear()
 }
 
+// sortedVars returns the ConfigVars sorted by name.
+func (e *EnvSet) sortedVars() []*ConfigVar {
+	all := e.Vars()
+	vars := make([]*ConfigVar, 0, len(all))
+	for _, v := range all {
+		vars = append(vars, v)
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
 func printVar(out io.Writer, v *ConfigVar, export, secrets bool) {
-	value := v.Value.String()
+	value := v.String()
 	if v.Secret {
 		if secrets {
-			value = v.Value.Get().(string)
+			value = v.value().(string)
 		} else {
 			if export {
 				return
@@ -370,7 +948,11 @@ func printVar(out io.Writer, v *ConfigVar, export, secrets bool) {
 		fmt.Fprintf(out, "export %s=\"%s\"\n", v.Name, value)
 	} else {
 		kv := fmt.Sprintf("%s=\"%s\"", v.Name, value)
-		fmt.Fprintf(out, "%-40s # %s\n", kv, v.Description)
+		usage := v.usage()
+		if r, ok := v.value().(Rollout); ok {
+			usage = strings.TrimSpace(usage + " [" + r.state() + "]")
+		}
+		fmt.Fprintf(out, "%-40s # %s\n", kv, usage)
 	}
 }
 
@@ -380,5 +962,6 @@ func NewEnvSet(name string) *EnvSet {
 	e := &EnvSet{
 		name: name,
 	}
+	e.Usage = e.defaultUsage
 	return e
 }
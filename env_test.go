@@ -2,8 +2,16 @@ package env
 
 import (
 	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -144,6 +152,56 @@ func TestRace(t *testing.T) {
 	<-done
 }
 
+func TestConcurrentAccess(t *testing.T) {
+	set := NewEnvSet("test")
+	t.Setenv("CONF_SHARED", "1")
+	for i := 0; i < 8; i++ {
+		t.Setenv(fmt.Sprintf("CONF_OPTION_%d", i), "bar")
+	}
+	set.Int("conf_shared", 0, "shared value")
+	shared := set.Var("conf_shared")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			set.String(fmt.Sprintf("conf_string_%d", i), "foo", "")
+			set.StringOption(fmt.Sprintf("conf_option_%d", i), "foo", []string{"foo", "bar"}, "")
+			set.Parse()
+			set.VisitAll(func(v *ConfigVar) { _, _ = v.String(), v.Options })
+			set.PrintEnv(io.Discard, false, false)
+			set.PrintDefaults(io.Discard)
+			for name := range set.Vars() {
+				set.Get(name)
+			}
+			if v := shared.Get(); v != 1 {
+				t.Errorf("expected: %d got: %v", 1, v)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, set.Vars(), 17)
+}
+
+func TestVarsCopy(t *testing.T) {
+	set := NewEnvSet("test")
+	set.String("conf_string", "foo", "")
+	vars := set.Vars()
+	delete(vars, "conf_string")
+	assert.NotNil(t, set.Var("conf_string"))
+}
+
+func TestVisitAllSorted(t *testing.T) {
+	set := NewEnvSet("test")
+	for _, name := range []string{"c", "a", "b"} {
+		set.String(name, "", "")
+	}
+	var names []string
+	set.VisitAll(func(v *ConfigVar) { names = append(names, v.Name) })
+	assert.Equal(t, []string{"A", "B", "C"}, names)
+}
+
 func TestPrintDefaults(t *testing.T) {
 	ResetForTesting()
 	PrintDefaults(nil) // TODO: replace with buffer and actually test
@@ -177,3 +235,361 @@ func TestClearEnv(t *testing.T) {
 	assert.Equal(t, "bar", foo)
 
 }
+
+func TestStringMap(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_MAP", "b:2, a:1")
+	m := StringMap("conf_map", map[string]string{"c": "3"}, "test map")
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, m)
+	assert.Equal(t, "a:1,b:2", Var("conf_map").Value.String())
+	assert.Equal(t, "c:3", Var("conf_map").Default)
+	assert.NoError(t, Err())
+}
+
+func TestStringMapSep(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_HEADERS", "X-Id=1;X-Env=prod")
+	m := StringMapSep("conf_headers", nil, ";", "=", "test headers")
+	assert.Equal(t, map[string]string{"X-Id": "1", "X-Env": "prod"}, m)
+	assert.Equal(t, "X-Env=prod;X-Id=1", Var("conf_headers").Value.String())
+}
+
+func TestStringMapInvalid(t *testing.T) {
+	for _, val := range []string{"a:1,a:2", "a", ":1"} {
+		ResetForTesting()
+		t.Setenv("CONF_MAP", val)
+		m := StringMap("conf_map", map[string]string{"a": "0"}, "test map")
+		assert.Equal(t, map[string]string{"a": "0"}, m, val)
+
+		var perr *ParseError
+		if assert.ErrorAs(t, Err(), &perr, val) {
+			assert.Equal(t, "CONF_MAP", perr.Name)
+			assert.Equal(t, val, perr.Value)
+		}
+	}
+}
+
+func TestIntMap(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_INT_MAP", "a:1,b:0x10")
+	m := IntMap("conf_int_map", nil, "test int map")
+	assert.Equal(t, map[string]int{"a": 1, "b": 16}, m)
+
+	t.Setenv("CONF_INT_MAP_BAD", "a:one")
+	m = IntMap("conf_int_map_bad", map[string]int{"a": 1}, "test int map")
+	assert.Equal(t, map[string]int{"a": 1}, m)
+	assert.Error(t, Err())
+}
+
+func TestFloat64Map(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_WEIGHTS", "a:0.5,b:1.5")
+	m := Float64Map("conf_weights", nil, "test float64 map")
+	assert.Equal(t, map[string]float64{"a": 0.5, "b": 1.5}, m)
+	assert.Equal(t, "a:0.5,b:1.5", Var("conf_weights").Value.String())
+}
+
+func TestIP(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_IP", "10.0.0.1")
+	ip := IP("conf_ip", nil, "test ip")
+	assert.Equal(t, "10.0.0.1", ip.String())
+	assert.NoError(t, Err())
+
+	t.Setenv("CONF_IP_BAD", "10.0.0")
+	ip = IP("conf_ip_bad", net.IPv4(127, 0, 0, 1), "test ip")
+	assert.Equal(t, "127.0.0.1", ip.String())
+	assert.Error(t, Err())
+}
+
+func TestIPNet(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_NET", "10.1.2.3/8")
+	n := IPNet("conf_net", nil, "test ipnet")
+	assert.Equal(t, "10.0.0.0/8", n.String())
+	assert.Equal(t, "", Var("conf_net").Default)
+}
+
+func TestIPAddrPrefix(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_ADDR", "::1")
+	t.Setenv("CONF_PREFIX", "192.168.0.0/16")
+	a := IPAddr("conf_addr", netip.Addr{}, "test addr")
+	p := IPPrefix("conf_prefix", netip.Prefix{}, "test prefix")
+	assert.Equal(t, netip.IPv6Loopback(), a)
+	assert.Equal(t, netip.MustParsePrefix("192.168.0.0/16"), p)
+	assert.NoError(t, Err())
+}
+
+func TestHostPort(t *testing.T) {
+	tests := map[string]string{
+		"example.com":      "example.com:80",
+		"example.com:8080": "example.com:8080",
+		"::1":              "[::1]:80",
+		"[::1]:8080":       "[::1]:8080",
+	}
+	for val, expected := range tests {
+		ResetForTesting()
+		t.Setenv("CONF_ADDR", val)
+		assert.Equal(t, expected, HostPort("conf_addr", "", "80", "test addr"), val)
+		assert.NoError(t, Err(), val)
+	}
+
+	for _, val := range []string{"example.com:http", "example.com:", "a:b:c"} {
+		ResetForTesting()
+		t.Setenv("CONF_ADDR", val)
+		assert.Equal(t, "localhost:80", HostPort("conf_addr", "localhost:80", "80", "test addr"), val)
+		assert.Error(t, Err(), val)
+	}
+}
+
+func TestTCPAddr(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_TCP", "127.0.0.1:8080")
+	resolve := TCPAddr("conf_tcp", "", "test tcp addr")
+	addr, err := resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, addr.Port)
+
+	t.Setenv("CONF_UDP", "127.0.0.1")
+	UDPAddr("conf_udp", "127.0.0.1:53", "test udp addr")
+	assert.Error(t, Err())
+}
+
+func TestURL(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_URL", "postgres://user:pass@db:5432/app")
+	u := URL("conf_url", nil, []string{"postgres"}, "test url")
+	assert.Equal(t, "db:5432", u.Host)
+	assert.Equal(t, "postgres://user:xxxxx@db:5432/app", Var("conf_url").Value.String())
+
+	def, _ := url.Parse("https://example.com")
+	t.Setenv("CONF_URL_SCHEME", "ftp://example.com")
+	u = URL("conf_url_scheme", def, []string{"http", "https"}, "test url")
+	assert.Equal(t, def, u)
+
+	t.Setenv("CONF_URL_REL", "/relative")
+	u = URL("conf_url_rel", def, nil, "test url")
+	assert.Equal(t, def, u)
+	assert.Len(t, Err().(interface{ Unwrap() []error }).Unwrap(), 2)
+}
+
+func TestSize(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_BUFFER", "64MiB")
+	assert.Equal(t, 64*MiB, Size("conf_buffer", 4*KiB, "test size"))
+	assert.Equal(t, "4KiB", Var("conf_buffer").Default)
+
+	t.Setenv("CONF_LIMIT", "2GiB")
+	assert.Equal(t, 512*MiB, SizeRange("conf_limit", 512*MiB, MiB, GiB, "test size range"))
+	assert.Error(t, Err())
+}
+
+func TestPercentage(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_PERCENT", "75%")
+	assert.Equal(t, Percent(75), Percentage("conf_percent", 50, "test percent"))
+
+	t.Setenv("CONF_PERCENT_HIGH", "150%")
+	assert.Equal(t, Percent(50), Percentage("conf_percent_high", 50, "test percent"))
+	assert.Error(t, Err())
+
+	ResetForTesting()
+	assert.Equal(t, Percent(150), PercentageRange("conf_percent_high", 100, 100, 200, "test percent range"))
+	assert.NoError(t, Err())
+}
+
+func TestFrequency(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_RATE", "5/min")
+	r := Frequency("conf_rate", Rate{100, time.Second}, "test rate")
+	assert.Equal(t, Rate{5, time.Minute}, r)
+	assert.Equal(t, "100/s", Var("conf_rate").Default)
+
+	t.Setenv("CONF_RATE_LIMITED", "1000/s")
+	r = FrequencyRange("conf_rate_limited", Rate{1, time.Second}, Rate{}, Rate{100, time.Second}, "test rate range")
+	assert.Equal(t, Rate{1, time.Second}, r)
+	assert.Error(t, Err())
+}
+
+func TestTime(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_TIME", "2024-05-15T10:30:00Z")
+	tm := Time("conf_time", time.Time{}, "test time")
+	assert.Equal(t, time.Date(2024, time.May, 15, 10, 30, 0, 0, time.UTC), tm)
+	assert.Equal(t, "", Var("conf_time").Default)
+	assert.Equal(t, "2024-05-15T10:30:00Z", Var("conf_time").Value.String())
+
+	t.Setenv("CONF_DATE", "15/05/2024")
+	tm = TimeLayout("conf_date", time.Time{}, []string{"2006-01-02", "02/01/2006"}, "test time layouts")
+	assert.Equal(t, time.Date(2024, time.May, 15, 0, 0, 0, 0, time.UTC), tm)
+	assert.Equal(t, "2024-05-15", Var("conf_date").Value.String())
+	assert.NoError(t, Err())
+
+	t.Setenv("CONF_DATE_BAD", "May 15")
+	TimeLayout("conf_date_bad", time.Time{}, []string{"2006-01-02", "02/01/2006"}, "test time layouts")
+	assert.Error(t, Err())
+}
+
+func TestLocation(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_TZ", "UTC")
+	assert.Equal(t, time.UTC, Location("conf_tz", time.Local, "test location"))
+
+	t.Setenv("CONF_TZ_BAD", "Mars/Olympus_Mons")
+	assert.Equal(t, time.Local, Location("conf_tz_bad", time.Local, "test location"))
+	assert.Error(t, Err())
+}
+
+func TestCron(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_WINDOW", "0 3 * * sun")
+	s := Cron("conf_window", MustParseSchedule("@daily"), "test cron")
+	assert.Equal(t, "0 3 * * sun", s.String())
+	assert.Equal(t, "@daily", Var("conf_window").Default)
+
+	t.Setenv("CONF_WINDOW_BAD", "every sunday")
+	assert.Nil(t, Cron("conf_window_bad", nil, "test cron"))
+	assert.Error(t, Err())
+}
+
+func TestBool(t *testing.T) {
+	tests := map[string]bool{
+		"true": true, "1": true, "YES": true, "y": true, "On": true, "enabled": true,
+		"false": false, "0": false, "no": false, "N": false, "off": false, "Disabled": false,
+	}
+	for val, expected := range tests {
+		ResetForTesting()
+		t.Setenv("CONF_BOOL", val)
+		assert.Equal(t, expected, Bool("conf_bool", !expected, "test bool"), val)
+		assert.NoError(t, Err(), val)
+	}
+
+	ResetForTesting()
+	t.Setenv("CONF_BOOL", "yep")
+	assert.True(t, Bool("conf_bool", true, "test bool"))
+	assert.EqualError(t, Err(), `env: invalid value "yep" for CONF_BOOL: unknown boolean "yep"`)
+}
+
+func TestBoolWords(t *testing.T) {
+	set := NewEnvSet("test")
+	set.BoolWords([]string{"Ja"}, []string{"Nein"})
+	t.Setenv("CONF_BOOL_JA", "ja")
+	t.Setenv("CONF_BOOL_YES", "yes")
+	assert.True(t, set.Bool("conf_bool_ja", false, "test bool"))
+	assert.False(t, set.Bool("conf_bool_yes", false, "test bool"))
+	assert.Error(t, set.Err())
+}
+
+func TestIntRange(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_INT8", "127")
+	t.Setenv("CONF_INT16", "-32768")
+	t.Setenv("CONF_INT32", "0x7fffffff")
+	t.Setenv("CONF_UINT8", "255")
+	t.Setenv("CONF_UINT16", "65535")
+	t.Setenv("CONF_UINT32", "4294967295")
+	t.Setenv("CONF_FLOAT32", "1.5")
+	assert.Equal(t, int8(127), Int8("conf_int8", 0, "int8 value"))
+	assert.Equal(t, int16(-32768), Int16("conf_int16", 0, "int16 value"))
+	assert.Equal(t, int32(math.MaxInt32), Int32("conf_int32", 0, "int32 value"))
+	assert.Equal(t, uint8(255), Uint8("conf_uint8", 0, "uint8 value"))
+	assert.Equal(t, uint16(65535), Uint16("conf_uint16", 0, "uint16 value"))
+	assert.Equal(t, uint32(math.MaxUint32), Uint32("conf_uint32", 0, "uint32 value"))
+	assert.Equal(t, float32(1.5), Float32("conf_float32", 0, "float32 value"))
+	assert.NoError(t, Err())
+}
+
+func TestIntOverflow(t *testing.T) {
+	ResetForTesting()
+	t.Setenv("CONF_INT8", "128")
+	t.Setenv("CONF_UINT8", "256")
+	t.Setenv("CONF_UINT16", "65536")
+	t.Setenv("CONF_INT", "9223372036854775808")
+	t.Setenv("CONF_UINT", "18446744073709551616")
+	t.Setenv("CONF_FLOAT32", "1e39")
+	assert.Equal(t, int8(1), Int8("conf_int8", 1, "int8 value"))
+	assert.Equal(t, uint8(1), Uint8("conf_uint8", 1, "uint8 value"))
+	assert.Equal(t, uint16(1), Uint16("conf_uint16", 1, "uint16 value"))
+	assert.Equal(t, 1, Int("conf_int", 1, "int value"))
+	assert.Equal(t, uint(1), Uint("conf_uint", 1, "uint value"))
+	assert.Equal(t, float32(1), Float32("conf_float32", 1, "float32 value"))
+
+	errs := Err().(interface{ Unwrap() []error }).Unwrap()
+	assert.Len(t, errs, 6)
+	for _, err := range errs {
+		assert.ErrorIs(t, err, strconv.ErrRange)
+	}
+}
+
+func TestSetSource(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetSource(MapSource{"CONF_STRING": "bar"})
+	t.Setenv("CONF_INT", "1")
+	assert.Equal(t, "bar", set.String("conf_string", "foo", ""))
+	assert.Equal(t, 0, set.Int("conf_int", 0, ""))
+
+	set.SetSource(nil)
+	assert.Equal(t, OSSource, set.Source())
+	assert.NoError(t, set.Parse())
+	assert.Equal(t, "foo", set.Var("conf_string").Get())
+	assert.Equal(t, 1, set.Var("conf_int").Get())
+}
+
+func TestOptionValidatedOnParse(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetDeferred(true)
+	set.SetSource(MapSource{"APP_MODE": "turbo", "APP_LEVEL": "2"})
+	set.StringOption("app_mode", "fast", []string{"fast", "safe"}, "")
+	set.IntOption("app_level", 1, []int{1, 2}, "")
+
+	var perr *ParseError
+	assert.ErrorAs(t, set.Parse(), &perr)
+	assert.Equal(t, "APP_MODE", perr.Name)
+	mode, err := set.GetString("app_mode")
+	assert.NoError(t, err)
+	assert.Equal(t, "fast", mode)
+	level, _ := set.GetInt("app_level")
+	assert.Equal(t, 2, level)
+	assert.Equal(t, "string", set.Var("app_mode").Type())
+
+	// Re-parsing starts again from the default.
+	set.SetSource(MapSource{"APP_LEVEL": "3"})
+	assert.Error(t, set.Parse())
+	level, _ = set.GetInt("app_level")
+	assert.Equal(t, 1, level)
+}
+
+func TestStringListSplit(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetSource(MapSource{"APP_TAGS": "a,b,c"})
+	assert.Equal(t, []string{"a", "b", "c"}, set.StringList("app_tags", nil, ""))
+}
+
+func TestParseBool(t *testing.T) {
+	for s, want := range map[string]bool{"yes": true, "T": true, "Off": false, "0": false} {
+		b, err := ParseBool(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, b, s)
+	}
+	_, err := ParseBool("maybe")
+	assert.Error(t, err)
+}
+
+func TestMapGetCopy(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetSource(MapSource{"APP_LIMITS": "a:1"})
+	tags := set.StringMap("app_tags", map[string]string{"env": "prod"}, "")
+	tags["env"] = "dev"
+	limits := set.IntMap("app_limits", nil, "")
+	limits["b"] = 2
+	weights := set.Float64Map("app_weights", map[string]float64{"x": 1}, "")
+	weights["y"] = 2
+
+	assert.Equal(t, "env:prod", set.Var("app_tags").String())
+	assert.Equal(t, "a:1", set.Var("app_limits").String())
+	assert.Equal(t, "x:1", set.Var("app_weights").String())
+	set.Var("app_tags").Get().(map[string]string)["env"] = "dev"
+	assert.NoError(t, set.Parse())
+	assert.Equal(t, map[string]string{"env": "prod"}, set.Var("app_tags").Get())
+}
@@ -0,0 +1,276 @@
+// Package envcheck defines an Analyzer that reports misuse of the env API:
+//
+//   - variables declared twice on the same EnvSet, which panics at runtime,
+//     including declarations on DefaultEnv in different packages
+//   - variable names that are not constants
+//   - empty descriptions
+//   - defaults missing from the options of StringOption, IntOption and the
+//     other Option functions
+//   - values of Secret variables passed to fmt, log or log/slog
+//
+// Names are compared uppercased, as with the default name mapper. The
+// analyzer can be run with go vet through cmd/envcheck:
+//
+//	go vet -vettool=$(which envcheck) ./...
+package envcheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/mattaitchison/env"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const envPath = "github.com/mattaitchison/env"
+
+// Analyzer reports misuse of the env API.
+var Analyzer = &analysis.Analyzer{
+	Name:      "envcheck",
+	Doc:       "report misuse of the github.com/mattaitchison/env API",
+	URL:       "https://pkg.go.dev/github.com/mattaitchison/env/envcheck",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(declared)},
+	Run:       run,
+}
+
+// declared is a package fact recording the variables declared on DefaultEnv
+// by a package and its dependencies, mapped to the position of the declaration.
+type declared struct {
+	Names map[string]string
+}
+
+func (*declared) AFact() {}
+
+func (d *declared) String() string {
+	names := make([]string, 0, len(d.Names))
+	for name := range d.Names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "declared(" + strings.Join(names, ", ") + ")"
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	// The wrappers of env pass their callers' names through.
+	if pass.Pkg.Path() == envPath {
+		return nil, nil
+	}
+	defaults := importDeclared(pass)
+	sets := map[types.Object]map[string]token.Pos{}
+	secrets := secretObjects(pass)
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn := typeutil.StaticCallee(pass.TypesInfo, call)
+		if fn == nil || fn.Pkg() == nil {
+			return
+		}
+		switch fn.Pkg().Path() {
+		case envPath:
+			args := params(fn, call)
+			if args["name"] == nil || args["description"] == nil {
+				return
+			}
+			name, ok := constString(pass, args["name"])
+			if !ok {
+				pass.Reportf(args["name"].Pos(), "env variable name should be a constant")
+				return
+			}
+			if fn.Name() == "Feature" || fn.Name() == "FeatureRollout" {
+				name = env.FeaturePrefix + name
+			}
+			name = strings.ToUpper(name)
+
+			if recv, isDefault := receiver(pass, call); isDefault {
+				if pos, ok := defaults[name]; ok {
+					pass.Reportf(args["name"].Pos(), "env variable %s already declared at %s", name, pos)
+				} else {
+					defaults[name] = pass.Fset.Position(args["name"].Pos()).String()
+				}
+			} else if recv != nil {
+				if sets[recv] == nil {
+					sets[recv] = map[string]token.Pos{}
+				}
+				if pos, ok := sets[recv][name]; ok {
+					pass.Reportf(args["name"].Pos(), "env variable %s already declared at %s", name, pass.Fset.Position(pos))
+				} else {
+					sets[recv][name] = args["name"].Pos()
+				}
+			}
+
+			if desc, ok := constString(pass, args["description"]); ok && strings.TrimSpace(desc) == "" {
+				pass.Reportf(args["description"].Pos(), "env variable %s has no description", name)
+			}
+			checkOptions(pass, name, args["defaultVal"], args["options"])
+
+		case "fmt", "log", "log/slog":
+			for _, arg := range call.Args {
+				if isSecret(pass, secrets, arg) {
+					pass.Reportf(arg.Pos(), "secret env variable passed to %s.%s", fn.Pkg().Name(), fn.Name())
+				}
+			}
+		}
+	})
+
+	pass.ExportPackageFact(&declared{Names: defaults})
+	return nil, nil
+}
+
+// importDeclared merges the declared facts of the imports of the package,
+// reporting variables declared by more than one of them.
+func importDeclared(pass *analysis.Pass) map[string]string {
+	names := map[string]string{}
+	seen := map[string]bool{}
+	for _, file := range pass.Files {
+		for _, spec := range file.Imports {
+			pkgName := pass.TypesInfo.PkgNameOf(spec)
+			if pkgName == nil || seen[pkgName.Imported().Path()] {
+				continue
+			}
+			seen[pkgName.Imported().Path()] = true
+
+			var fact declared
+			if !pass.ImportPackageFact(pkgName.Imported(), &fact) {
+				continue
+			}
+			for name, pos := range fact.Names {
+				if prev, ok := names[name]; ok && prev != pos {
+					pass.Reportf(spec.Pos(), "env variable %s declared at %s and %s", name, prev, pos)
+					continue
+				}
+				names[name] = pos
+			}
+		}
+	}
+	return names
+}
+
+// params maps the parameter names of fn to the arguments of call.
+func params(fn *types.Func, call *ast.CallExpr) map[string]ast.Expr {
+	sig := fn.Type().(*types.Signature)
+	args := map[string]ast.Expr{}
+	for i := 0; i < sig.Params().Len() && i < len(call.Args); i++ {
+		args[sig.Params().At(i).Name()] = call.Args[i]
+	}
+	return args
+}
+
+// receiver returns the variable holding the EnvSet a method is called on, and
+// whether the call declares a variable on DefaultEnv. It returns nil if the
+// EnvSet is not held by a variable.
+func receiver(pass *analysis.Pass, call *ast.CallExpr) (types.Object, bool) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, true
+	}
+	if selection := pass.TypesInfo.Selections[sel]; selection == nil {
+		// A qualified identifier, env.Int.
+		return nil, true
+	}
+	var id *ast.Ident
+	switch x := ast.Unparen(sel.X).(type) {
+	case *ast.Ident:
+		id = x
+	case *ast.SelectorExpr:
+		id = x.Sel
+	default:
+		return nil, false
+	}
+	obj := pass.TypesInfo.Uses[id]
+	if obj != nil && obj.Pkg() != nil && obj.Pkg().Path() == envPath && obj.Name() == "DefaultEnv" {
+		return nil, true
+	}
+	return obj, false
+}
+
+func constString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// checkOptions reports a constant default missing from a literal slice of
+// constant options.
+func checkOptions(pass *analysis.Pass, name string, def, options ast.Expr) {
+	lit, ok := options.(*ast.CompositeLit)
+	if def == nil || !ok {
+		return
+	}
+	defVal := pass.TypesInfo.Types[def].Value
+	if defVal == nil {
+		return
+	}
+	for _, elt := range lit.Elts {
+		v := pass.TypesInfo.Types[elt].Value
+		if v == nil || constant.Compare(v, token.EQL, defVal) {
+			return
+		}
+	}
+	pass.Reportf(def.Pos(), "default %s of env variable %s is not one of its options", defVal, name)
+}
+
+// secretObjects returns the variables assigned the result of Secret.
+func secretObjects(pass *analysis.Pass) map[types.Object]bool {
+	objs := map[types.Object]bool{}
+	assign := func(lhs []*ast.Ident, rhs []ast.Expr) {
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, id := range lhs {
+			if !isSecretCall(pass, rhs[i]) {
+				continue
+			}
+			if obj := pass.TypesInfo.ObjectOf(id); obj != nil {
+				objs[obj] = true
+			}
+		}
+	}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ValueSpec:
+				assign(n.Names, n.Values)
+			case *ast.AssignStmt:
+				var ids []*ast.Ident
+				for _, expr := range n.Lhs {
+					id, _ := expr.(*ast.Ident)
+					if id == nil {
+						id = ast.NewIdent("_")
+					}
+					ids = append(ids, id)
+				}
+				assign(ids, n.Rhs)
+			}
+			return true
+		})
+	}
+	return objs
+}
+
+func isSecretCall(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == envPath && fn.Name() == "Secret"
+}
+
+func isSecret(pass *analysis.Pass, secrets map[types.Object]bool, expr ast.Expr) bool {
+	if isSecretCall(pass, expr) {
+		return true
+	}
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	return ok && secrets[pass.TypesInfo.Uses[id]]
+}
@@ -0,0 +1,12 @@
+package envcheck_test
+
+import (
+	"testing"
+
+	"github.com/mattaitchison/env/envcheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), envcheck.Analyzer, "a", "b", "c", "github.com/mattaitchison/env")
+}
@@ -0,0 +1,47 @@
+package a // want package:"declared\\(FEATURE_BETA, HOST, HOST_SUFFIX, KEY, LEVEL, MODE, PASSWORD, PORT, TOKEN, WORKERS\\)"
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/mattaitchison/env"
+)
+
+const hostName = "host"
+
+var (
+	host = env.String(hostName, "localhost", "Server host")
+	port = env.Int("port", 80, "Server port")
+	_    = env.Int("PORT", 81, "Server port again") // want `env variable PORT already declared at .*a.go:14:`
+	_    = env.DefaultEnv.Int("Port", 82, "Port")   // want `env variable PORT already declared`
+
+	_ = env.String(hostName+"_suffix", "", "")                             // want `env variable HOST_SUFFIX has no description`
+	_ = env.StringOption("mode", "slow", []string{"fast", "safe"}, "Mode") // want `default "slow" of env variable MODE is not one of its options`
+	_ = env.StringOption("level", "info", []string{"debug", "info"}, "Level")
+	_ = env.IntOption("workers", 3, []int{1, 2, 4}, "Workers") // want `default 3 of env variable WORKERS is not one of its options`
+
+	password = env.Secret("password", "Database password")
+
+	_ = env.Feature("beta", false, "Beta features")
+	_ = env.String("feature_beta", "", "Beta") // want `env variable FEATURE_BETA already declared`
+)
+
+func dynamic(name string) {
+	env.String(name, "", "Dynamic") // want `env variable name should be a constant`
+	env.Alias(name, "other")
+}
+
+func sets() {
+	set, other := &env.EnvSet{}, &env.EnvSet{}
+	set.Int("port", 1, "Port")
+	set.Int("PORT", 2, "Port") // want `env variable PORT already declared`
+	other.Int("port", 3, "Port")
+}
+
+func leak() {
+	token := env.Secret("token", "API token")
+	fmt.Println(host, port)
+	fmt.Println(password)                // want `secret env variable passed to fmt.Println`
+	log.Printf("token %s", token)        // want `secret env variable passed to log.Printf`
+	fmt.Sprint(env.Secret("key", "Key")) // want `secret env variable passed to fmt.Sprint`
+}
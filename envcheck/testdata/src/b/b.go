@@ -0,0 +1,5 @@
+package b // want package:"declared\\(HOST\\)"
+
+import "github.com/mattaitchison/env"
+
+var Host = env.String("host", "localhost", "Server host")
@@ -0,0 +1,10 @@
+package c // want package:"declared\\(FEATURE_BETA, HOST, .*\\)"
+
+import (
+	_ "a"
+	_ "b" // want `env variable HOST declared at .*a.go:13:20 and .*b.go:5:23`
+
+	"github.com/mattaitchison/env"
+)
+
+var _ = env.Int("port", 8080, "Port") // want `env variable PORT already declared at .*a.go:14:`
@@ -0,0 +1,29 @@
+// Package env is a stub of github.com/mattaitchison/env for tests.
+package env
+
+type EnvSet struct{}
+
+var DefaultEnv = &EnvSet{}
+
+func (e *EnvSet) String(name string, defaultVal string, description string) string { return "" }
+func (e *EnvSet) Int(name string, defaultVal int, description string) int          { return 0 }
+func (e *EnvSet) Secret(name string, description string) string                    { return "" }
+
+func String(name string, defaultVal string, description string) string { return "" }
+func Int(name string, defaultVal int, description string) int          { return 0 }
+func Secret(name string, description string) string                    { return "" }
+func StringOption(name string, defaultVal string, options []string, description string) string {
+	return ""
+}
+func IntOption(name string, defaultVal int, options []int, description string) int { return 0 }
+func Alias(name string, alias string)                                              {}
+
+type FeatureFlag struct{}
+
+func Feature(name string, defaultVal bool, description string) *FeatureFlag { return nil }
+
+func (e *EnvSet) Bool(name string, defaultVal bool, description string) bool { return false }
+
+func Bool(name string, defaultVal bool, description string) bool {
+	return DefaultEnv.Bool(name, defaultVal, description)
+}
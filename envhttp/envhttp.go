@@ -0,0 +1,87 @@
+// Package envhttp serves the variables of an EnvSet over HTTP, with secrets
+// masked, so the configuration of a running program can be inspected.
+//
+// Importing the package registers a handler for env.DefaultEnv at /debug/env
+// on http.DefaultServeMux, in the manner of expvar:
+//
+//	import _ "github.com/mattaitchison/env/envhttp"
+//
+// The handler serves HTML, or JSON when the request has ?format=json or
+// accepts application/json:
+//
+//	curl -H 'Accept: application/json' localhost:8080/debug/env
+package envhttp
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/mattaitchison/env"
+)
+
+func init() {
+	http.Handle("/debug/env", Handler(env.DefaultEnv))
+}
+
+// Var is the JSON representation of a ConfigVar.
+type Var struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Value       string `json:"value"`
+	Default     string `json:"default"`
+	Description string `json:"description"`
+	Origin      string `json:"origin"`
+	Secret      bool   `json:"secret,omitempty"`
+}
+
+// Handler returns a handler that serves the variables of set sorted by name.
+func Handler(set *env.EnvSet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var vars []Var
+		set.VisitAll(func(v *env.ConfigVar) {
+			vars = append(vars, Var{
+				Name:        v.Name,
+				Type:        v.Type(),
+				Value:       v.Masked(),
+				Default:     v.Default,
+				Description: v.Description,
+				Origin:      v.Origin(),
+				Secret:      v.Secret,
+			})
+		})
+
+		if wantsJSON(r) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			if vars == nil {
+				vars = []Var{}
+			}
+			enc.Encode(vars)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		page.Execute(w, vars)
+	})
+}
+
+func wantsJSON(r *http.Request) bool {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return f == "json"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+var page = template.Must(template.New("env").Parse(`<!DOCTYPE html>
+<html>
+<head><title>/debug/env</title></head>
+<body>
+<table>
+<tr><th>Name</th><th>Type</th><th>Value</th><th>Default</th><th>Origin</th><th>Description</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Value}}</td><td>{{.Default}}</td><td>{{.Origin}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,67 @@
+package envhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattaitchison/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSet() *env.EnvSet {
+	set := env.NewEnvSet("test")
+	set.SetSource(env.MapSource{"APP_PORT": "8080", "APP_TOKEN": "12345678"})
+	set.Int("app_port", 80, "Listen port")
+	set.String("app_name", "<app>", "Name shown in pages")
+	set.Secret("app_token", "API token")
+	return set
+}
+
+func TestHandlerJSON(t *testing.T) {
+	h := Handler(newTestSet())
+	for _, r := range []*http.Request{
+		httptest.NewRequest("GET", "/debug/env?format=json", nil),
+		func() *http.Request {
+			r := httptest.NewRequest("GET", "/debug/env", nil)
+			r.Header.Set("Accept", "application/json")
+			return r
+		}(),
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+		var vars []Var
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &vars))
+		assert.Equal(t, []Var{
+			{Name: "APP_NAME", Type: "string", Value: "<app>", Default: "<app>", Description: "Name shown in pages", Origin: "default"},
+			{Name: "APP_PORT", Type: "int", Value: "8080", Default: "80", Description: "Listen port", Origin: "APP_PORT"},
+			{Name: "APP_TOKEN", Type: "string", Value: "XXXX5678", Default: "", Description: "API token", Origin: "APP_TOKEN", Secret: true},
+		}, vars)
+	}
+}
+
+func TestHandlerHTML(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler(newTestSet()).ServeHTTP(w, httptest.NewRequest("GET", "/debug/env", nil))
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.Contains(t, body, "<td>APP_PORT</td><td>int</td><td>8080</td><td>80</td><td>APP_PORT</td><td>Listen port</td>")
+	assert.Contains(t, body, "&lt;app&gt;")
+	assert.Contains(t, body, "XXXX5678")
+	assert.NotContains(t, body, "12345678")
+}
+
+func TestHandlerEmpty(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler(env.NewEnvSet("empty")).ServeHTTP(w, httptest.NewRequest("GET", "/debug/env?format=json", nil))
+	assert.JSONEq(t, "[]", w.Body.String())
+}
+
+func TestDefaultServeMux(t *testing.T) {
+	w := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(w, httptest.NewRequest("GET", "/debug/env", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,82 @@
+// Package envtest provides helpers for testing code configured with env.
+package envtest
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mattaitchison/env"
+)
+
+// NewEnvSet returns an EnvSet named after the test that reads variables from
+// vars instead of the environment of the process. Warnings are written to the
+// test log.
+func NewEnvSet(t testing.TB, vars map[string]string) *env.EnvSet {
+	t.Helper()
+	src := make(env.MapSource, len(vars))
+	for k, v := range vars {
+		src[k] = v
+	}
+	set := env.NewEnvSet(t.Name())
+	set.SetSource(src)
+	set.SetLogger(logger{t})
+	return set
+}
+
+// Set overrides the environment variables in vars for set until the test
+// and its subtests complete, then restores the previous environment. An empty
+// value unsets a variable. set is parsed again both times so declared
+// variables pick up the change; parse errors are left for the test to check
+// with set.Err.
+func Set(t testing.TB, set *env.EnvSet, vars map[string]string) {
+	t.Helper()
+	base := set.Source()
+	o := overlay{base: base, vars: make(map[string]string, len(vars))}
+	for k, v := range vars {
+		o.vars[k] = v
+	}
+	set.SetSource(o)
+	set.Parse()
+	t.Cleanup(func() {
+		set.SetSource(base)
+		set.Parse()
+	})
+}
+
+// overlay is a Source that takes vars in preference to base.
+type overlay struct {
+	base env.Source
+	vars map[string]string
+}
+
+func (o overlay) LookupEnv(key string) (string, bool) {
+	if v, ok := o.vars[key]; ok {
+		return v, v != ""
+	}
+	return o.base.LookupEnv(key)
+}
+
+func (o overlay) Environ() []string {
+	var environ []string
+	for _, kv := range o.base.Environ() {
+		k, _, _ := strings.Cut(kv, "=")
+		if _, ok := o.vars[k]; !ok {
+			environ = append(environ, kv)
+		}
+	}
+	for k, v := range o.vars {
+		if v != "" {
+			environ = append(environ, k+"="+v)
+		}
+	}
+	sort.Strings(environ)
+	return environ
+}
+
+type logger struct{ t testing.TB }
+
+func (l logger) Printf(format string, v ...interface{}) {
+	l.t.Helper()
+	l.t.Logf(format, v...)
+}
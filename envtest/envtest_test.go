@@ -0,0 +1,64 @@
+package envtest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mattaitchison/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEnvSet(t *testing.T) {
+	t.Setenv("APP_PORT", "9999")
+	set := NewEnvSet(t, map[string]string{"APP_HOST": "example.com"})
+	assert.Equal(t, "example.com", set.String("app_host", "localhost", ""))
+	assert.Equal(t, 80, set.Int("app_port", 80, ""))
+	assert.Equal(t, []string{"APP_HOST=example.com"}, set.Source().Environ())
+}
+
+func TestSet(t *testing.T) {
+	set := NewEnvSet(t, map[string]string{"APP_HOST": "example.com", "APP_PORT": "8080"})
+	set.String("app_host", "localhost", "")
+	set.Int("app_port", 80, "")
+	set.Bool("app_debug", false, "")
+
+	t.Run("override", func(t *testing.T) {
+		Set(t, set, map[string]string{"APP_PORT": "9090", "APP_HOST": "", "APP_DEBUG": "on"})
+		assert.Equal(t, 9090, set.Var("app_port").Get())
+		assert.Equal(t, "localhost", set.Var("app_host").Get())
+		assert.Equal(t, true, set.Var("app_debug").Get())
+		assert.Equal(t, []string{"APP_DEBUG=on", "APP_PORT=9090"}, set.Source().Environ())
+
+		t.Run("nested", func(t *testing.T) {
+			Set(t, set, map[string]string{"APP_PORT": "bad"})
+			assert.Equal(t, 80, set.Var("app_port").Get())
+			assert.Error(t, set.Err())
+		})
+		assert.Equal(t, 9090, set.Var("app_port").Get())
+		assert.NoError(t, set.Err())
+	})
+
+	assert.Equal(t, 8080, set.Var("app_port").Get())
+	assert.Equal(t, "example.com", set.Var("app_host").Get())
+	assert.Equal(t, false, set.Var("app_debug").Get())
+}
+
+func TestSetDoesNotTouchOS(t *testing.T) {
+	set := NewEnvSet(t, nil)
+	set.String("envtest_unset", "", "")
+	Set(t, set, map[string]string{"ENVTEST_UNSET": "value"})
+	assert.Equal(t, "value", set.Var("envtest_unset").Get())
+	_, ok := os.LookupEnv("ENVTEST_UNSET")
+	assert.False(t, ok)
+}
+
+func TestSetChild(t *testing.T) {
+	set := NewEnvSet(t, map[string]string{"APP_PORT": "8080"})
+	set.Int("app_port", 80, "")
+	child := set.Child()
+	assert.NoError(t, child.Set("app_port", "9090"))
+
+	Set(t, child, map[string]string{"APP_PORT": "7070"})
+	assert.Equal(t, 9090, child.Var("app_port").Get())
+	assert.Equal(t, env.OriginOverride, child.Var("app_port").Origin())
+}
@@ -0,0 +1,27 @@
+package env
+
+import "expvar"
+
+// Publish registers an expvar.Var with the given name that renders the values
+// of all non-secret variables as a JSON object keyed by Name. The values are
+// read each time the Var is, so they follow Parse. Like expvar.Publish, it
+// panics if the name is already registered.
+func (e *EnvSet) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		vars := make(map[string]string)
+		e.VisitAll(func(v *ConfigVar) {
+			if !v.Secret {
+				vars[v.Name] = v.String()
+			}
+		})
+		return vars
+	}))
+}
+
+// Publish registers an expvar.Var with the given name that renders the values
+// of all non-secret variables as a JSON object keyed by Name. The values are
+// read each time the Var is, so they follow Parse. Like expvar.Publish, it
+// panics if the name is already registered.
+func Publish(name string) {
+	DefaultEnv.Publish(name)
+}
@@ -0,0 +1,27 @@
+package env
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublish(t *testing.T) {
+	set := NewEnvSet("test")
+	src := MapSource{"APP_PORT": "8080", "APP_TOKEN": "12345678"}
+	set.SetSource(src)
+	set.Int("app_port", 80, "")
+	set.String("app_host", "localhost", "")
+	set.Secret("app_token", "")
+	set.Publish("test_publish")
+
+	v := expvar.Get("test_publish")
+	assert.JSONEq(t, `{"APP_HOST": "localhost", "APP_PORT": "8080"}`, v.String())
+
+	src["APP_PORT"] = "9090"
+	set.Parse()
+	assert.JSONEq(t, `{"APP_HOST": "localhost", "APP_PORT": "9090"}`, v.String())
+
+	assert.Panics(t, func() { set.Publish("test_publish") })
+}
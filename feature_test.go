@@ -0,0 +1,99 @@
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRollout(t *testing.T) {
+	tests := map[string]Rollout{
+		"true":            {Percent: 100},
+		"Off":             {},
+		"25%":             {Percent: 25},
+		"12.5%, alice":    {Percent: 12.5, Allow: []string{"alice"}},
+		"false,alice,bob": {Allow: []string{"alice", "bob"}},
+	}
+	for s, want := range tests {
+		r, err := ParseRollout(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, r, s)
+	}
+
+	for _, s := range []string{"", "alice", "150%", "-1%", "x%"} {
+		_, err := ParseRollout(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRolloutString(t *testing.T) {
+	assert.Equal(t, "true", Rollout{Percent: 100}.String())
+	assert.Equal(t, "false", Rollout{}.String())
+	assert.Equal(t, "25%,alice", Rollout{Percent: 25, Allow: []string{"alice"}}.String())
+	assert.Equal(t, "false,alice,bob", Rollout{Allow: []string{"alice", "bob"}}.String())
+}
+
+func TestRolloutEnabledFor(t *testing.T) {
+	r := Rollout{Percent: 25, Allow: []string{"alice"}}
+	assert.True(t, r.EnabledFor("FEATURE_X", "alice"))
+
+	enabled := 0
+	for i := 0; i < 10000; i++ {
+		id := fmt.Sprint("user-", i)
+		on := r.EnabledFor("FEATURE_X", id)
+		assert.Equal(t, on, r.EnabledFor("FEATURE_X", id), "stable")
+		if on {
+			enabled++
+			// Growing the rollout keeps enabled IDs enabled.
+			assert.True(t, Rollout{Percent: 50}.EnabledFor("FEATURE_X", id))
+		}
+	}
+	assert.InDelta(t, 2500, enabled, 250)
+
+	assert.True(t, Rollout{Percent: 100}.EnabledFor("FEATURE_X", "anyone"))
+	assert.False(t, Rollout{}.EnabledFor("FEATURE_X", "anyone"))
+}
+
+func TestFeature(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetSource(MapSource{
+		"FEATURE_NEW_CHECKOUT": "false,alice",
+		"FEATURE_DARK_MODE":    "yes",
+	})
+	checkout := set.Feature("new_checkout", true, "New checkout flow")
+	dark := set.Feature("dark_mode", false, "Dark mode")
+	beta := set.FeatureRollout("beta", Rollout{Percent: 10}, "Beta features")
+
+	assert.False(t, checkout.Enabled())
+	assert.True(t, checkout.EnabledFor("alice"))
+	assert.False(t, checkout.EnabledFor("bob"))
+	assert.True(t, dark.Enabled())
+	assert.Equal(t, Rollout{Percent: 10}, beta.Rollout())
+	assert.Equal(t, "FEATURE_BETA", beta.Var().Name)
+	assert.Equal(t, "feature", beta.Var().Type())
+
+	assert.True(t, set.FeatureEnabled("new_checkout", "alice"))
+	assert.False(t, set.FeatureEnabled("missing", "alice"))
+
+	child := set.Child()
+	assert.NoError(t, child.Set("FEATURE_NEW_CHECKOUT", "true"))
+	assert.True(t, child.FeatureEnabled("new_checkout", "bob"))
+	assert.False(t, set.FeatureEnabled("new_checkout", "bob"))
+
+	var buf bytes.Buffer
+	set.PrintEnv(&buf, false, false)
+	assert.Equal(t, `FEATURE_BETA="10%"                       # Beta features [enabled for 10%]
+FEATURE_DARK_MODE="true"                 # Dark mode [enabled]
+FEATURE_NEW_CHECKOUT="false,alice"       # New checkout flow [enabled for 1 ID]
+`, buf.String())
+}
+
+func TestFeatureInvalid(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetSource(MapSource{"FEATURE_X": "sometimes"})
+	f := set.Feature("x", true, "")
+	assert.True(t, f.Enabled())
+	assert.Error(t, set.Err())
+}
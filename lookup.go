@@ -0,0 +1,175 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUndefined is returned when looking up a variable that was not declared.
+var ErrUndefined = errors.New("env: undefined variable")
+
+// TypeError is returned by the typed getters when a variable holds a value of
+// a different type.
+type TypeError struct {
+	Name  string
+	Want  string
+	Value interface{}
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("env: %s is %T, not %s", e.Name, e.Value, e.Want)
+}
+
+// Lookup retrieves a ConfigVar by declared or environment name, e.g. either
+// "test_string" or "TEST_STRING", and reports whether it exists. A child
+// EnvSet falls back to its parent.
+func (e *EnvSet) Lookup(name string) (*ConfigVar, bool) {
+	e.Lock()
+	v, ok := e.lookup(name)
+	e.Unlock()
+	if !ok && e.parent != nil {
+		return e.parent.Lookup(name)
+	}
+	return v, ok
+}
+
+// lookup is Lookup without the fallback to the parent. e must be locked.
+func (e *EnvSet) lookup(name string) (*ConfigVar, bool) {
+	if v, ok := e.vars[name]; ok {
+		return v, true
+	}
+	v, ok := e.vars[e.mapName(name)]
+	return v, ok
+}
+
+// Lookup retrieves a ConfigVar by declared or environment name, e.g. either
+// "test_string" or "TEST_STRING", and reports whether it exists.
+func Lookup(name string) (*ConfigVar, bool) {
+	return DefaultEnv.Lookup(name)
+}
+
+// Has reports whether a variable with the declared or environment name exists.
+func (e *EnvSet) Has(name string) bool {
+	_, ok := e.Lookup(name)
+	return ok
+}
+
+// Has reports whether a variable with the declared or environment name exists.
+func Has(name string) bool {
+	return DefaultEnv.Has(name)
+}
+
+// getAs returns the value of the variable name as a T.
+func getAs[T any](e *EnvSet, name, want string) (T, error) {
+	var zero T
+	val, err := e.Get(name)
+	if err != nil {
+		return zero, err
+	}
+	t, ok := val.(T)
+	if !ok {
+		return zero, &TypeError{Name: e.Var(name).Name, Want: want, Value: val}
+	}
+	return t, nil
+}
+
+// GetString returns the value of the string variable name.
+func (e *EnvSet) GetString(name string) (string, error) {
+	return getAs[string](e, name, "string")
+}
+
+// GetString returns the value of the string variable name.
+func GetString(name string) (string, error) {
+	return DefaultEnv.GetString(name)
+}
+
+// GetStringList returns the value of the string list variable name.
+func (e *EnvSet) GetStringList(name string) ([]string, error) {
+	return getAs[[]string](e, name, "[]string")
+}
+
+// GetStringList returns the value of the string list variable name.
+func GetStringList(name string) ([]string, error) {
+	return DefaultEnv.GetStringList(name)
+}
+
+// GetStringMap returns the value of the string map variable name.
+func (e *EnvSet) GetStringMap(name string) (map[string]string, error) {
+	return getAs[map[string]string](e, name, "map[string]string")
+}
+
+// GetStringMap returns the value of the string map variable name.
+func GetStringMap(name string) (map[string]string, error) {
+	return DefaultEnv.GetStringMap(name)
+}
+
+// GetBool returns the value of the bool variable name.
+func (e *EnvSet) GetBool(name string) (bool, error) {
+	return getAs[bool](e, name, "bool")
+}
+
+// GetBool returns the value of the bool variable name.
+func GetBool(name string) (bool, error) {
+	return DefaultEnv.GetBool(name)
+}
+
+// GetInt returns the value of the int variable name.
+func (e *EnvSet) GetInt(name string) (int, error) {
+	return getAs[int](e, name, "int")
+}
+
+// GetInt returns the value of the int variable name.
+func GetInt(name string) (int, error) {
+	return DefaultEnv.GetInt(name)
+}
+
+// GetInt64 returns the value of the int64 variable name.
+func (e *EnvSet) GetInt64(name string) (int64, error) {
+	return getAs[int64](e, name, "int64")
+}
+
+// GetInt64 returns the value of the int64 variable name.
+func GetInt64(name string) (int64, error) {
+	return DefaultEnv.GetInt64(name)
+}
+
+// GetUint returns the value of the uint variable name.
+func (e *EnvSet) GetUint(name string) (uint, error) {
+	return getAs[uint](e, name, "uint")
+}
+
+// GetUint returns the value of the uint variable name.
+func GetUint(name string) (uint, error) {
+	return DefaultEnv.GetUint(name)
+}
+
+// GetUint64 returns the value of the uint64 variable name.
+func (e *EnvSet) GetUint64(name string) (uint64, error) {
+	return getAs[uint64](e, name, "uint64")
+}
+
+// GetUint64 returns the value of the uint64 variable name.
+func GetUint64(name string) (uint64, error) {
+	return DefaultEnv.GetUint64(name)
+}
+
+// GetFloat64 returns the value of the float64 variable name.
+func (e *EnvSet) GetFloat64(name string) (float64, error) {
+	return getAs[float64](e, name, "float64")
+}
+
+// GetFloat64 returns the value of the float64 variable name.
+func GetFloat64(name string) (float64, error) {
+	return DefaultEnv.GetFloat64(name)
+}
+
+// GetDuration returns the value of the time.Duration variable name.
+func (e *EnvSet) GetDuration(name string) (time.Duration, error) {
+	return getAs[time.Duration](e, name, "time.Duration")
+}
+
+// GetDuration returns the value of the time.Duration variable name.
+func GetDuration(name string) (time.Duration, error) {
+	return DefaultEnv.GetDuration(name)
+}
@@ -0,0 +1,72 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	set := NewEnvSet("test")
+	set.String("test_string", "0", "")
+
+	for _, name := range []string{"test_string", "TEST_STRING", "Test_String"} {
+		v, ok := set.Lookup(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, "TEST_STRING", v.Name)
+		assert.True(t, set.Has(name), name)
+		assert.NotNil(t, set.Var(name), name)
+	}
+
+	v, ok := set.Lookup("other")
+	assert.False(t, ok)
+	assert.Nil(t, v)
+	assert.False(t, set.Has("other"))
+	assert.Nil(t, set.Var("other"))
+}
+
+func TestTypedGetters(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetSource(MapSource{"APP_PORT": "8080", "APP_TAGS": "a:1"})
+	set.String("app_host", "localhost", "")
+	set.Int("app_port", 80, "")
+	set.Bool("app_debug", true, "")
+	set.Duration("app_timeout", time.Second, "")
+	set.StringMap("app_tags", nil, "")
+	set.Secret("app_token", "")
+
+	host, err := set.GetString("app_host")
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", host)
+	port, err := set.GetInt("APP_PORT")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, port)
+	debug, err := set.GetBool("app_debug")
+	assert.NoError(t, err)
+	assert.True(t, debug)
+	timeout, err := set.GetDuration("app_timeout")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, timeout)
+	tags, err := set.GetStringMap("app_tags")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1"}, tags)
+	_, err = set.GetString("app_token")
+	assert.NoError(t, err)
+
+	_, err = set.GetString("app_port")
+	assert.EqualError(t, err, "env: APP_PORT is int, not string")
+	var terr *TypeError
+	assert.ErrorAs(t, err, &terr)
+	_, err = set.GetInt64("app_port")
+	assert.ErrorAs(t, err, &terr)
+
+	_, err = set.GetInt("missing")
+	assert.ErrorIs(t, err, ErrUndefined)
+
+	deferred := NewEnvSet("deferred")
+	deferred.SetDeferred(true)
+	deferred.Int("app_port", 80, "")
+	_, err = deferred.GetInt("app_port")
+	assert.ErrorIs(t, err, ErrNotParsed)
+}
@@ -0,0 +1,76 @@
+package env
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NameMapper maps a declared variable name to the name of the environment
+// variable it is read from.
+type NameMapper func(name string) string
+
+var (
+	// UpperCase maps log_level to LOG_LEVEL. It is the default.
+	UpperCase NameMapper = strings.ToUpper
+
+	// ExactCase uses names as declared.
+	ExactCase NameMapper = func(name string) string { return name }
+
+	// ScreamingSnake maps camelCase names such as httpPort or apiURL to
+	// HTTP_PORT and API_URL. Dots and dashes become underscores.
+	ScreamingSnake NameMapper = screamingSnake
+
+	// DottedPath maps dotted paths such as server.http.port to
+	// SERVER_HTTP_PORT.
+	DottedPath NameMapper = func(name string) string {
+		return strings.ToUpper(strings.ReplaceAll(name, ".", "_"))
+	}
+
+	// DashToUnderscore maps names such as log-level to LOG_LEVEL.
+	DashToUnderscore NameMapper = func(name string) string {
+		return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	}
+)
+
+func screamingSnake(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if r == '.' || r == '-' {
+			r = '_'
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// SetNameMapper sets how declared names are mapped to environment variable
+// names. If m is nil, UpperCase is used. It should be called before any
+// variables, aliases or the prefix are declared.
+func (e *EnvSet) SetNameMapper(m NameMapper) {
+	e.Lock()
+	defer e.Unlock()
+	e.mapper = m
+}
+
+// SetNameMapper sets how declared names are mapped to environment variable
+// names. If m is nil, UpperCase is used. It should be called before any
+// variables, aliases or the prefix are declared.
+func SetNameMapper(m NameMapper) {
+	DefaultEnv.SetNameMapper(m)
+}
+
+// mapName maps name with the EnvSet's NameMapper. e must be locked.
+func (e *EnvSet) mapName(name string) string {
+	if e.mapper == nil {
+		return UpperCase(name)
+	}
+	return e.mapper(name)
+}
@@ -0,0 +1,55 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameMappers(t *testing.T) {
+	tests := []struct {
+		mapper   NameMapper
+		name     string
+		expected string
+	}{
+		{UpperCase, "log_level", "LOG_LEVEL"},
+		{ExactCase, "logLevel", "logLevel"},
+		{ScreamingSnake, "httpPort", "HTTP_PORT"},
+		{ScreamingSnake, "HTTPServer", "HTTP_SERVER"},
+		{ScreamingSnake, "apiURL", "API_URL"},
+		{ScreamingSnake, "v2Enabled", "V2_ENABLED"},
+		{ScreamingSnake, "already_snake", "ALREADY_SNAKE"},
+		{ScreamingSnake, "server.httpPort", "SERVER_HTTP_PORT"},
+		{DottedPath, "server.http.port", "SERVER_HTTP_PORT"},
+		{DashToUnderscore, "log-level", "LOG_LEVEL"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.mapper(tt.name), tt.name)
+	}
+}
+
+func TestSetNameMapper(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetNameMapper(ScreamingSnake)
+	set.SetSource(MapSource{"HTTP_PORT": "8080", "OLD_PORT": "1", "APP_X": "1"})
+	set.SetPrefix("app.")
+	set.Alias("adminPort", "oldPort")
+	assert.Equal(t, 8080, set.Int("httpPort", 80, ""))
+	assert.Equal(t, 1, set.Int("adminPort", 0, ""))
+
+	assert.Equal(t, "HTTP_PORT", set.Var("httpPort").Name)
+	assert.Same(t, set.Var("httpPort"), set.Var("HTTP_PORT"))
+	assert.Len(t, set.Unknown(), 1)
+	_, ok := set.Snapshot().Lookup("httpPort")
+	assert.True(t, ok)
+	assert.Panics(t, func() { set.Int("HTTP_PORT", 80, "") })
+}
+
+func TestExactCase(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetNameMapper(ExactCase)
+	set.SetSource(MapSource{"http_port": "8080", "HTTP_PORT": "9090"})
+	assert.Equal(t, 8080, set.Int("http_port", 80, ""))
+	assert.Equal(t, 9090, set.Int("HTTP_PORT", 80, ""))
+	assert.Nil(t, set.Var("Http_Port"))
+}
@@ -0,0 +1,202 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// OriginDefault is the origin of a variable that has its default value.
+const OriginDefault = "default"
+
+// ErrNotParsed is returned when a variable of a deferred EnvSet is read
+// before Parse has been called.
+var ErrNotParsed = errors.New("env: variable read before Parse")
+
+// SetDeferred sets whether declaring a variable reads it from the environment
+// immediately, which is the default, or only registers it until Parse is
+// called. While deferred, the typed declarations such as String return their
+// default value; read variables with Get after Parse instead.
+func (e *EnvSet) SetDeferred(deferred bool) {
+	e.Lock()
+	defer e.Unlock()
+	e.deferred = deferred
+}
+
+// SetDeferred sets whether declaring a variable reads it from the environment
+// immediately, which is the default, or only registers it until Parse is
+// called. While deferred, the typed declarations such as String return their
+// default value; read variables with Get after Parse instead.
+func SetDeferred(deferred bool) {
+	DefaultEnv.SetDeferred(deferred)
+}
+
+// Parse reads every declared variable from the environment, starting from its
+// default value, and returns all the errors encountered as Err does.
+// Variables declared after Parse are read immediately. Parse may be called
+// again to re-read the environment; values given with Set are kept.
+func (e *EnvSet) Parse() error {
+	var warnings []string
+	var logger Logger
+	defer func() {
+		for _, w := range warnings {
+			logger.Printf("%s", w)
+		}
+	}()
+
+	e.Lock()
+	defer e.Unlock()
+	e.errs = nil
+	names := make([]string, 0, len(e.vars))
+	for name := range e.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		warnings = append(warnings, e.resolve(e.vars[name])...)
+	}
+	for _, v := range e.rejected {
+		warnings = append(warnings, e.resolve(v)...)
+	}
+	e.parsed = true
+	logger = e.output()
+	return e.err()
+}
+
+// Parse reads every declared variable from the environment, starting from its
+// default value, and returns all the errors encountered as Err does.
+// Variables declared after Parse are read immediately. Parse may be called
+// again to re-read the environment; values given with Set are kept.
+func Parse() error {
+	return DefaultEnv.Parse()
+}
+
+// Parsed reports whether Parse has been called.
+func (e *EnvSet) Parsed() bool {
+	e.Lock()
+	defer e.Unlock()
+	return e.parsed
+}
+
+// Parsed reports whether Parse has been called.
+func Parsed() bool {
+	return DefaultEnv.Parsed()
+}
+
+// Get returns the value of the variable. It panics if the variable belongs to
+// a deferred EnvSet that has not been parsed.
+func (v *ConfigVar) Get() interface{} {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if !v.resolved {
+		panic("env: " + v.Name + " read before Parse")
+	}
+	return v.Value.Get()
+}
+
+// String returns the value of the variable as text.
+func (v *ConfigVar) String() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.Value.String()
+}
+
+// Origin returns the name of the environment variable the value was read
+// from, which differs from Name when an alias was used, or "default".
+func (v *ConfigVar) Origin() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if v.origin == "" {
+		return OriginDefault
+	}
+	return v.origin
+}
+
+// Type returns the name of the type of v's Value: the result of its Type
+// method if it implements Typer, otherwise the Go type of its Get result, or
+// "value" if that is nil.
+func (v *ConfigVar) Type() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if t, ok := v.Value.(Typer); ok {
+		return t.Type()
+	}
+	val := v.Value.Get()
+	if val == nil {
+		return "value"
+	}
+	return fmt.Sprintf("%T", val)
+}
+
+// value is Get without the check that the variable has been read.
+func (v *ConfigVar) value() interface{} {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.Value.Get()
+}
+
+// Get returns the value of the variable name. It returns ErrUndefined if there
+// is no such variable and ErrNotParsed if the EnvSet is deferred and has not
+// been parsed.
+func (e *EnvSet) Get(name string) (interface{}, error) {
+	v, ok := e.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUndefined, name)
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if !v.resolved {
+		return nil, ErrNotParsed
+	}
+	return v.Value.Get(), nil
+}
+
+// Get returns the value of the variable name. It returns ErrUndefined if there
+// is no such variable and ErrNotParsed if the EnvSet is deferred and has not
+// been parsed.
+func Get(name string) (interface{}, error) {
+	return DefaultEnv.Get(name)
+}
+
+// resolve sets v from the environment, starting from its default value, and
+// returns any warnings to log. Readers using Get see either the old or the new
+// value. A value given with Set is kept. e must be locked.
+func (e *EnvSet) resolve(v *ConfigVar) []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.origin == OriginOverride {
+		return nil
+	}
+	if v.resolved && v.reset != nil {
+		v.reset()
+	}
+	v.resolved = true
+	v.origin = ""
+	key, val := lookupVar(e.env(), v)
+	if val == "" {
+		if v.Required {
+			e.errs = append(e.errs, &RequiredError{Name: v.Name})
+		}
+		return nil
+	}
+	if err := v.Value.Set(val); err != nil {
+		e.errs = append(e.errs, &ParseError{Name: key, Value: val, Err: err})
+	} else {
+		v.origin = key
+	}
+	return deprecationWarnings(v, key)
+}
+
+// saveValue returns a func that restores value to its current state, or nil
+// if value is not a pointer. The copy is shallow, which is enough for Values
+// whose Set replaces their contents rather than modifying them.
+func saveValue(value Value) func() {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil
+	}
+	saved := reflect.New(rv.Elem().Type()).Elem()
+	saved.Set(rv.Elem())
+	return func() { rv.Elem().Set(saved) }
+}
@@ -0,0 +1,70 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeferred(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetDeferred(true)
+	t.Setenv("APP_PORT", "8080")
+	t.Setenv("APP_DEBUG", "maybe")
+
+	assert.Equal(t, 80, set.Int("app_port", 80, "port"))
+	assert.False(t, set.Bool("app_debug", false, "debug"))
+	assert.False(t, set.Parsed())
+	assert.Panics(t, func() { set.Var("app_port").Get() })
+	_, err := set.Get("app_port")
+	assert.Equal(t, ErrNotParsed, err)
+
+	// Sources can be set up between declaring and parsing.
+	t.Setenv("APP_PORT", "9090")
+	err = set.Parse()
+	assert.EqualError(t, err, `env: invalid value "maybe" for APP_DEBUG: unknown boolean "maybe"`)
+	assert.Equal(t, err.Error(), set.Err().Error())
+	assert.True(t, set.Parsed())
+	assert.Equal(t, 9090, set.Var("app_port").Get())
+	port, err := set.Get("app_port")
+	assert.NoError(t, err)
+	assert.Equal(t, 9090, port)
+
+	// Declarations after Parse are read immediately.
+	t.Setenv("APP_HOST", "example.com")
+	assert.Equal(t, "example.com", set.String("app_host", "localhost", "host"))
+}
+
+func TestParseAgain(t *testing.T) {
+	set := NewEnvSet("test")
+	t.Setenv("APP_PORT", "8080")
+	t.Setenv("APP_TAGS", "a:1")
+	assert.Equal(t, 8080, set.Int("app_port", 80, "port"))
+	set.StringMap("app_tags", map[string]string{"b": "2"}, "tags")
+	set.Secret("app_token", "token")
+
+	t.Setenv("APP_PORT", "")
+	t.Setenv("APP_TAGS", "c:3")
+	t.Setenv("APP_TOKEN", "secret")
+	assert.NoError(t, set.Parse())
+	assert.Equal(t, 80, set.Var("app_port").Get())
+	assert.Equal(t, map[string]string{"c": "3"}, set.Var("app_tags").Get())
+	assert.Equal(t, "secret", set.Var("app_token").Get())
+
+	t.Setenv("APP_TAGS", "")
+	t.Setenv("APP_PORT", "eighty")
+	assert.Error(t, set.Parse())
+	assert.Equal(t, 80, set.Var("app_port").Get())
+	assert.Equal(t, map[string]string{"b": "2"}, set.Var("app_tags").Get())
+
+	t.Setenv("APP_PORT", "81")
+	assert.NoError(t, set.Parse())
+	assert.NoError(t, set.Err())
+}
+
+func TestGetUndefined(t *testing.T) {
+	set := NewEnvSet("test")
+	_, err := set.Get("missing")
+	assert.EqualError(t, err, "env: undefined variable: missing")
+	assert.ErrorIs(t, err, ErrUndefined)
+}
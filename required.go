@@ -0,0 +1,31 @@
+package env
+
+// RequiredError is recorded when a required variable is not set.
+type RequiredError struct {
+	Name string
+}
+
+func (e *RequiredError) Error() string {
+	return "env: " + e.Name + " is required but not set"
+}
+
+// Require marks the variables names as required: a *RequiredError is reported
+// by Err and Parse when one of them is not set, and PrintDefaults shows them
+// as required. Require must be called before the names are declared.
+func (e *EnvSet) Require(names ...string) {
+	e.Lock()
+	defer e.Unlock()
+	if e.required == nil {
+		e.required = make(map[string]bool)
+	}
+	for _, name := range names {
+		e.required[e.mapName(name)] = true
+	}
+}
+
+// Require marks the variables names as required: a *RequiredError is reported
+// by Err and Parse when one of them is not set, and PrintDefaults shows them
+// as required. Require must be called before the names are declared.
+func Require(names ...string) {
+	DefaultEnv.Require(names...)
+}
@@ -0,0 +1,26 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequire(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetSource(MapSource{"APP_HOST": "example.com"})
+	set.Require("app_host", "app_token")
+	set.String("app_host", "", "")
+	set.Secret("app_token", "")
+	set.String("app_other", "", "")
+
+	assert.True(t, set.Var("app_host").Required)
+	assert.False(t, set.Var("app_other").Required)
+	var required *RequiredError
+	assert.ErrorAs(t, set.Err(), &required)
+	assert.Equal(t, "APP_TOKEN", required.Name)
+	assert.EqualError(t, set.Err(), "env: APP_TOKEN is required but not set")
+
+	set.SetSource(MapSource{"APP_HOST": "example.com", "APP_TOKEN": "s3cret"})
+	assert.NoError(t, set.Parse())
+}
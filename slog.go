@@ -0,0 +1,82 @@
+package env
+
+import "log/slog"
+
+// LogOptions controls the attributes EnvSet.LogValue produces.
+type LogOptions struct {
+	// Origin adds where each value was read from; see ConfigVar.Origin.
+	Origin bool
+	// Changed adds whether each value differs from its default.
+	Changed bool
+}
+
+// SetLogOptions sets the attributes LogValue produces for each variable.
+func (e *EnvSet) SetLogOptions(o LogOptions) {
+	e.Lock()
+	defer e.Unlock()
+	e.logOptions = o
+}
+
+// SetLogOptions sets the attributes LogValue produces for each variable.
+func SetLogOptions(o LogOptions) {
+	DefaultEnv.SetLogOptions(o)
+}
+
+// LogValue implements slog.LogValuer. It returns a group with an attribute
+// per variable, sorted by name, with secrets masked. If LogOptions are set
+// each variable is a group of its value and the requested details.
+func (e *EnvSet) LogValue() slog.Value {
+	e.Lock()
+	o := e.logOptions
+	e.Unlock()
+	vars := e.sortedVars()
+	attrs := make([]slog.Attr, len(vars))
+	for i, v := range vars {
+		attrs[i] = slog.Attr{Key: v.Name, Value: v.logValue(o)}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer. It returns the value as text with
+// secrets masked.
+func (v *ConfigVar) LogValue() slog.Value {
+	return v.logValue(LogOptions{})
+}
+
+func (v *ConfigVar) logValue(o LogOptions) slog.Value {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	value := v.masked()
+	if !o.Origin && !o.Changed {
+		return slog.StringValue(value)
+	}
+	attrs := []slog.Attr{slog.String("value", value)}
+	if o.Origin {
+		origin := v.origin
+		if origin == "" {
+			origin = OriginDefault
+		}
+		attrs = append(attrs, slog.String("origin", origin))
+	}
+	if o.Changed {
+		attrs = append(attrs, slog.Bool("changed", v.Value.String() != v.Default))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Masked returns the value as text, masked like the values of Secret if the
+// variable is secret.
+func (v *ConfigVar) Masked() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.masked()
+}
+
+// masked is Masked for a locked ConfigVar.
+func (v *ConfigVar) masked() string {
+	s := v.Value.String()
+	if _, ok := v.Value.(*secretValue); v.Secret && !ok {
+		s = newSecretValue(s).String()
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package env
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestLogValue(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetSource(MapSource{"APP_PORT": "8080", "APP_TOKEN": "12345678"})
+	set.Int("app_port", 80, "")
+	set.String("app_host", "localhost", "")
+	set.Secret("app_token", "")
+	v, _ := set.TryNewVar(newStringValue("hunter22"), "app_password", "")
+	v.Secret = true
+
+	var buf bytes.Buffer
+	newTestLogger(&buf).Info("config", "env", set)
+	assert.Equal(t, `level=INFO msg=config env.APP_HOST=localhost env.APP_PASSWORD=XXXXer22 env.APP_PORT=8080 env.APP_TOKEN=XXXX5678`+"\n", buf.String())
+
+	buf.Reset()
+	newTestLogger(&buf).Info("config", "port", set.Var("app_port"))
+	assert.Equal(t, "level=INFO msg=config port=8080\n", buf.String())
+}
+
+func TestLogValueOptions(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetSource(MapSource{"APP_PORT": "8080"})
+	set.Int("app_port", 80, "")
+	set.String("app_host", "localhost", "")
+	set.SetLogOptions(LogOptions{Origin: true, Changed: true})
+
+	var buf bytes.Buffer
+	newTestLogger(&buf).Info("config", "env", set)
+	assert.Equal(t, "level=INFO msg=config "+
+		"env.APP_HOST.value=localhost env.APP_HOST.origin=default env.APP_HOST.changed=false "+
+		"env.APP_PORT.value=8080 env.APP_PORT.origin=APP_PORT env.APP_PORT.changed=true\n", buf.String())
+
+	set.SetLogOptions(LogOptions{Origin: true})
+	buf.Reset()
+	newTestLogger(&buf).Info("config", "env", set)
+	assert.Equal(t, "level=INFO msg=config "+
+		"env.APP_HOST.value=localhost env.APP_HOST.origin=default "+
+		"env.APP_PORT.value=8080 env.APP_PORT.origin=APP_PORT\n", buf.String())
+}
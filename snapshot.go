@@ -0,0 +1,155 @@
+package env
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SnapshotVar is the state of a ConfigVar recorded by Snapshot.
+type SnapshotVar struct {
+	Value  string // value as text, including secrets
+	Masked string // value as text with secrets masked
+	Origin string // see ConfigVar.Origin
+	Secret bool
+}
+
+// Snapshot is an immutable record of the values of an EnvSet at one time.
+type Snapshot struct {
+	vars    map[string]SnapshotVar
+	mapName NameMapper
+}
+
+// Snapshot records the current values of all defined ConfigVars.
+func (e *EnvSet) Snapshot() Snapshot {
+	vars := make(map[string]SnapshotVar)
+	for _, v := range e.sortedVars() {
+		vars[v.Name] = v.snapshot()
+	}
+	e.Lock()
+	defer e.Unlock()
+	return Snapshot{vars: vars, mapName: e.mapper}
+}
+
+// TakeSnapshot records the current values of all defined ConfigVars.
+func TakeSnapshot() Snapshot {
+	return DefaultEnv.Snapshot()
+}
+
+func (v *ConfigVar) snapshot() SnapshotVar {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	s := SnapshotVar{
+		Value:  v.Value.String(),
+		Masked: v.masked(),
+		Origin: v.origin,
+		Secret: v.Secret,
+	}
+	if s.Origin == "" {
+		s.Origin = OriginDefault
+	}
+	if str, ok := v.Value.Get().(string); ok && v.Secret {
+		s.Value = str
+	}
+	return s
+}
+
+// Names returns the names of the recorded variables in lexicographical order.
+func (s Snapshot) Names() []string {
+	names := make([]string, 0, len(s.vars))
+	for name := range s.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the recorded state of the variable with the declared or
+// environment name.
+func (s Snapshot) Lookup(name string) (SnapshotVar, bool) {
+	if v, ok := s.vars[name]; ok {
+		return v, true
+	}
+	mapName := s.mapName
+	if mapName == nil {
+		mapName = UpperCase
+	}
+	v, ok := s.vars[mapName(name)]
+	return v, ok
+}
+
+// Len returns the number of recorded variables.
+func (s Snapshot) Len() int { return len(s.vars) }
+
+// ChangeKind is the kind of difference between two snapshots.
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Removed
+	Changed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change describes a variable that differs between two snapshots. Old is the
+// zero SnapshotVar for added variables and New for removed ones.
+type Change struct {
+	Name string
+	Kind ChangeKind
+	Old  SnapshotVar
+	New  SnapshotVar
+}
+
+// String describes the change with secrets masked.
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s added: %q (%s)", c.Name, c.New.Masked, c.New.Origin)
+	case Removed:
+		return fmt.Sprintf("%s removed: %q (%s)", c.Name, c.Old.Masked, c.Old.Origin)
+	}
+	return fmt.Sprintf("%s changed: %q (%s) -> %q (%s)", c.Name, c.Old.Masked, c.Old.Origin, c.New.Masked, c.New.Origin)
+}
+
+// Diff lists the changes from s to other, sorted by name.
+type Diff []Change
+
+// String describes the changes one per line with secrets masked.
+func (d Diff) String() string {
+	lines := make([]string, len(d))
+	for i, c := range d {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff returns the variables that were added, removed or changed in value or
+// origin going from s to other.
+func (s Snapshot) Diff(other Snapshot) Diff {
+	var d Diff
+	for name, old := range s.vars {
+		if v, ok := other.vars[name]; !ok {
+			d = append(d, Change{Name: name, Kind: Removed, Old: old})
+		} else if v != old {
+			d = append(d, Change{Name: name, Kind: Changed, Old: old, New: v})
+		}
+	}
+	for name, v := range other.vars {
+		if _, ok := s.vars[name]; !ok {
+			d = append(d, Change{Name: name, Kind: Added, New: v})
+		}
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i].Name < d[j].Name })
+	return d
+}
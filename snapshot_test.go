@@ -0,0 +1,64 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	set := NewEnvSet("test")
+	set.Alias("app_port", "port")
+	t.Setenv("PORT", "8080")
+	t.Setenv("APP_TOKEN", "12345678")
+	set.Int("app_port", 80, "")
+	set.String("app_host", "localhost", "")
+	set.Secret("app_token", "")
+
+	s := set.Snapshot()
+	assert.Equal(t, []string{"APP_HOST", "APP_PORT", "APP_TOKEN"}, s.Names())
+	assert.Equal(t, 3, s.Len())
+	v, ok := s.Lookup("app_port")
+	assert.True(t, ok)
+	assert.Equal(t, SnapshotVar{Value: "8080", Masked: "8080", Origin: "PORT"}, v)
+	v, _ = s.Lookup("APP_HOST")
+	assert.Equal(t, OriginDefault, v.Origin)
+	v, _ = s.Lookup("APP_TOKEN")
+	assert.Equal(t, SnapshotVar{Value: "12345678", Masked: "XXXX5678", Origin: "APP_TOKEN", Secret: true}, v)
+
+	// Snapshots don't change when the EnvSet does.
+	t.Setenv("APP_PORT", "9090")
+	set.Parse()
+	v, _ = s.Lookup("app_port")
+	assert.Equal(t, "8080", v.Value)
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	set := NewEnvSet("test")
+	t.Setenv("APP_PORT", "8080")
+	t.Setenv("APP_TOKEN", "12345678")
+	set.Int("app_port", 80, "")
+	set.String("app_host", "localhost", "")
+	set.Secret("app_token", "")
+	set.Bool("app_debug", false, "")
+	before := set.Snapshot()
+	assert.Empty(t, before.Diff(before))
+
+	t.Setenv("APP_PORT", "")
+	t.Setenv("APP_TOKEN", "87655678")
+	t.Setenv("APP_DEBUG", "false")
+	set.Parse()
+	set.String("app_name", "test", "")
+	after := set.Snapshot()
+
+	d := before.Diff(after)
+	assert.Equal(t, []string{"APP_DEBUG", "APP_NAME", "APP_PORT", "APP_TOKEN"}, []string{d[0].Name, d[1].Name, d[2].Name, d[3].Name})
+	assert.Equal(t, `APP_DEBUG changed: "false" (default) -> "false" (APP_DEBUG)
+APP_NAME added: "test" (default)
+APP_PORT changed: "8080" (APP_PORT) -> "80" (default)
+APP_TOKEN changed: "XXXX5678" (APP_TOKEN) -> "XXXX5678" (APP_TOKEN)`, d.String())
+
+	d = after.Diff(before)
+	assert.Equal(t, Removed, d[1].Kind)
+	assert.Equal(t, `APP_NAME removed: "test" (default)`, d[1].String())
+}
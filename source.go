@@ -0,0 +1,72 @@
+package env
+
+import (
+	"os"
+	"sort"
+)
+
+// Source is the environment an EnvSet reads variables from.
+type Source interface {
+	// LookupEnv returns the value of the variable key and whether it is set.
+	LookupEnv(key string) (string, bool)
+	// Environ returns all the variables as key=value strings.
+	Environ() []string
+}
+
+// OSSource is the environment of the process. It is the Source of an EnvSet
+// unless SetSource is called.
+var OSSource Source = osSource{}
+
+type osSource struct{}
+
+func (osSource) LookupEnv(key string) (string, bool) { return os.LookupEnv(key) }
+
+func (osSource) Environ() []string { return os.Environ() }
+
+// MapSource is a Source backed by a map, e.g. for tests.
+type MapSource map[string]string
+
+func (m MapSource) LookupEnv(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m MapSource) Environ() []string {
+	environ := make([]string, 0, len(m))
+	for k, v := range m {
+		environ = append(environ, k+"="+v)
+	}
+	sort.Strings(environ)
+	return environ
+}
+
+// SetSource sets the environment variables are read from. If s is nil the
+// environment of the process is used. Variables that have already been read
+// are only affected by the next Parse.
+func (e *EnvSet) SetSource(s Source) {
+	e.Lock()
+	defer e.Unlock()
+	e.source = s
+}
+
+// SetSource sets the environment variables are read from. If s is nil the
+// environment of the process is used. Variables that have already been read
+// are only affected by the next Parse.
+func SetSource(s Source) {
+	DefaultEnv.SetSource(s)
+}
+
+// Source returns the environment variables are read from.
+func (e *EnvSet) Source() Source {
+	e.Lock()
+	defer e.Unlock()
+	return e.env()
+}
+
+// env is Source for a locked EnvSet.
+func (e *EnvSet) env() Source {
+	if e.source == nil {
+		return OSSource
+	}
+	return e.source
+}
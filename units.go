@@ -0,0 +1,181 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ByteSize is a size in bytes such as a buffer size or memory limit.
+type ByteSize uint64
+
+// Decimal and binary byte size units.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+
+	KiB ByteSize = 1 << 10
+	MiB ByteSize = 1 << 20
+	GiB ByteSize = 1 << 30
+	TiB ByteSize = 1 << 40
+	PiB ByteSize = 1 << 50
+)
+
+var byteSizeUnits = map[string]ByteSize{
+	"": Byte, "b": Byte,
+	"k": KB, "kb": KB, "ki": KiB, "kib": KiB,
+	"m": MB, "mb": MB, "mi": MiB, "mib": MiB,
+	"g": GB, "gb": GB, "gi": GiB, "gib": GiB,
+	"t": TB, "tb": TB, "ti": TiB, "tib": TiB,
+	"p": PB, "pb": PB, "pi": PiB, "pib": PiB,
+}
+
+// ParseByteSize parses a size such as 1048576, 64MiB or 1.5GB. Units are case
+// insensitive; K, M, G, T and P are powers of 1000 and Ki, Mi, Gi, Ti and Pi
+// are powers of 1024, with or without a trailing B.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	mult, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q in size %q", s[i:], s)
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	size := math.Round(n * float64(mult))
+	if size >= math.MaxUint64 {
+		return 0, fmt.Errorf("size %q is too large", s)
+	}
+	return ByteSize(size), nil
+}
+
+// String returns the size using the largest unit that represents it exactly,
+// preferring binary units, e.g. 64MiB, 1500MB or 10B.
+func (b ByteSize) String() string {
+	units := []struct {
+		size ByteSize
+		name string
+	}{
+		{PiB, "PiB"}, {TiB, "TiB"}, {GiB, "GiB"}, {MiB, "MiB"}, {KiB, "KiB"},
+		{PB, "PB"}, {TB, "TB"}, {GB, "GB"}, {MB, "MB"}, {KB, "KB"},
+	}
+	for _, u := range units {
+		if b >= u.size && b%u.size == 0 {
+			return strconv.FormatUint(uint64(b/u.size), 10) + u.name
+		}
+	}
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
+
+// Percent is a percentage where 100 is the whole.
+type Percent float64
+
+// ParsePercent parses a percentage such as 75% or 12.5%. The % sign is
+// optional.
+func ParsePercent(s string) (Percent, error) {
+	s = strings.TrimSpace(s)
+	n, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, "%")), 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	return Percent(n), nil
+}
+
+// Fraction returns the percentage as a fraction of 1, e.g. 0.75 for 75%.
+func (p Percent) Fraction() float64 { return float64(p) / 100 }
+
+func (p Percent) String() string {
+	return strconv.FormatFloat(float64(p), 'f', -1, 64) + "%"
+}
+
+// Rate is a number of events per time period, e.g. 100/s.
+type Rate struct {
+	Count float64
+	Per   time.Duration
+}
+
+var rateUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second, "sec": time.Second, "second": time.Second,
+	"m": time.Minute, "min": time.Minute, "minute": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hour": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour,
+}
+
+// ParseRate parses a rate such as 100/s, 5/min, 10/hour or 3/500ms. The period
+// is either a unit name or a time.Duration.
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	num, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("missing period in rate %q", s)
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || n < 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return Rate{}, fmt.Errorf("invalid count in rate %q", s)
+	}
+	period = strings.TrimSpace(period)
+	per, ok := rateUnits[strings.ToLower(period)]
+	if !ok {
+		per, err = time.ParseDuration(period)
+		if err != nil {
+			return Rate{}, fmt.Errorf("invalid period in rate %q", s)
+		}
+	}
+	if per <= 0 {
+		return Rate{}, errors.New("rate period must be positive")
+	}
+	return Rate{Count: n, Per: per}, nil
+}
+
+// PerSecond returns the number of events per second.
+func (r Rate) PerSecond() float64 {
+	if r.Per <= 0 {
+		return 0
+	}
+	return r.Count / r.Per.Seconds()
+}
+
+// Interval returns the time between events, or 0 if the rate is zero.
+func (r Rate) Interval() time.Duration {
+	if r.Count <= 0 {
+		return 0
+	}
+	return time.Duration(float64(r.Per) / r.Count)
+}
+
+// String formats r so that ParseRate accepts it. A rate without a period,
+// such as the zero Rate, is zero and is formatted as 0/s.
+func (r Rate) String() string {
+	if r.Per <= 0 {
+		return "0/s"
+	}
+	count := strconv.FormatFloat(r.Count, 'f', -1, 64)
+	switch r.Per {
+	case time.Second:
+		return count + "/s"
+	case time.Minute:
+		return count + "/min"
+	case time.Hour:
+		return count + "/h"
+	case 24 * time.Hour:
+		return count + "/d"
+	}
+	return count + "/" + r.Per.String()
+}
@@ -0,0 +1,99 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]ByteSize{
+		"1048576": MiB,
+		"64MiB":   64 * MiB,
+		"64mib":   64 * MiB,
+		"64Mi":    64 * MiB,
+		"1.5GB":   1500 * MB,
+		"1.5 KiB": 1536,
+		"10k":     10 * KB,
+		"0":       0,
+	}
+	for s, expected := range tests {
+		b, err := ParseByteSize(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, b, s)
+	}
+
+	for _, s := range []string{"", "MiB", "-1MB", "1XB", "1.2.3KB", "20EB", "99999PB"} {
+		_, err := ParseByteSize(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestByteSizeString(t *testing.T) {
+	tests := map[ByteSize]string{
+		0:          "0B",
+		10:         "10B",
+		64 * MiB:   "64MiB",
+		1500 * MB:  "1500MB",
+		KB:         "1KB",
+		2048 * KiB: "2MiB",
+	}
+	for b, expected := range tests {
+		assert.Equal(t, expected, b.String())
+		parsed, err := ParseByteSize(b.String())
+		assert.NoError(t, err)
+		assert.Equal(t, b, parsed)
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	p, err := ParsePercent("75%")
+	assert.NoError(t, err)
+	assert.Equal(t, Percent(75), p)
+	assert.Equal(t, 0.75, p.Fraction())
+	assert.Equal(t, "75%", p.String())
+
+	p, err = ParsePercent(" 12.5 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "12.5%", p.String())
+
+	for _, s := range []string{"", "%", "half", "NaN%"} {
+		_, err := ParsePercent(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := map[string]Rate{
+		"100/s":    {100, time.Second},
+		"5/min":    {5, time.Minute},
+		"10/Hour":  {10, time.Hour},
+		"3/500ms":  {3, 500 * time.Millisecond},
+		"0.5 / 2m": {0.5, 2 * time.Minute},
+	}
+	for s, expected := range tests {
+		r, err := ParseRate(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, r, s)
+	}
+
+	for _, s := range []string{"", "100", "x/s", "-1/s", "1/fortnight", "1/0s"} {
+		_, err := ParseRate(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRate(t *testing.T) {
+	r := Rate{Count: 5, Per: time.Minute}
+	assert.Equal(t, "5/min", r.String())
+	assert.Equal(t, 12*time.Second, r.Interval())
+	assert.InDelta(t, 5.0/60, r.PerSecond(), 1e-9)
+	assert.Equal(t, "3/500ms", Rate{3, 500 * time.Millisecond}.String())
+	assert.Equal(t, time.Duration(0), Rate{}.Interval())
+
+	assert.Equal(t, "0/s", Rate{}.String())
+	r, err := ParseRate(Rate{}.String())
+	assert.NoError(t, err)
+	assert.Zero(t, r.PerSecond())
+}
@@ -0,0 +1,123 @@
+package env
+
+import (
+	"sort"
+	"strings"
+)
+
+// UnknownVar is a variable in the environment that has the EnvSet's prefix
+// but was not declared, usually because of a typo.
+type UnknownVar struct {
+	Name       string
+	Suggestion string // closest declared name, if there is one
+}
+
+func (u *UnknownVar) Error() string {
+	if u.Suggestion != "" {
+		return "env: unknown variable " + u.Name + ", did you mean " + u.Suggestion + "?"
+	}
+	return "env: unknown variable " + u.Name
+}
+
+// SetPrefix sets the prefix shared by the variables of the EnvSet, e.g.
+// REGISTRATOR_. It is used to find unknown variables.
+func (e *EnvSet) SetPrefix(prefix string) {
+	e.Lock()
+	defer e.Unlock()
+	e.prefix = e.mapName(prefix)
+}
+
+// SetPrefix sets the prefix shared by the variables of the EnvSet, e.g.
+// REGISTRATOR_. It is used to find unknown variables.
+func SetPrefix(prefix string) {
+	DefaultEnv.SetPrefix(prefix)
+}
+
+// SetStrict sets whether unknown variables are reported as errors by Err.
+func (e *EnvSet) SetStrict(strict bool) {
+	e.Lock()
+	defer e.Unlock()
+	e.strict = strict
+}
+
+// SetStrict sets whether unknown variables are reported as errors by Err.
+func SetStrict(strict bool) {
+	DefaultEnv.SetStrict(strict)
+}
+
+// Unknown returns the variables in the environment that have the EnvSet's
+// prefix but are neither declared nor aliases of a declared variable, sorted
+// by name. It returns nil if the EnvSet has no prefix.
+func (e *EnvSet) Unknown() []*UnknownVar {
+	e.Lock()
+	defer e.Unlock()
+	return e.unknown()
+}
+
+// Unknown returns the variables in the environment that have the EnvSet's
+// prefix but are neither declared nor aliases of a declared variable, sorted
+// by name. It returns nil if the EnvSet has no prefix.
+func Unknown() []*UnknownVar {
+	return DefaultEnv.Unknown()
+}
+
+// unknown is Unknown for a locked EnvSet.
+func (e *EnvSet) unknown() []*UnknownVar {
+	if e.prefix == "" {
+		return nil
+	}
+	known := make(map[string]bool)
+	var names []string
+	for _, v := range e.vars {
+		known[v.Name] = true
+		names = append(names, v.Name)
+		for _, alias := range v.Aliases {
+			known[alias] = true
+		}
+	}
+	sort.Strings(names)
+
+	var unknown []*UnknownVar
+	for _, kv := range e.env().Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, e.prefix) || known[name] {
+			continue
+		}
+		unknown = append(unknown, &UnknownVar{Name: name, Suggestion: closest(name, names)})
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Name < unknown[j].Name })
+	return unknown
+}
+
+// closest returns the name in names with the smallest edit distance to s, or
+// "" if none of them is close enough to be a likely typo.
+func closest(s string, names []string) string {
+	best, bestDist := "", len(s)/4+2
+	for _, name := range names {
+		if d := editDistance(s, name); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,49 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknown(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetPrefix("registrator_")
+	set.Alias("registrator_ttl_refresh", "registrator_ttl")
+	set.Bool("registrator_internal", false, "")
+	set.Int("registrator_ttl_refresh", 0, "")
+
+	t.Setenv("REGISTRATOR_INTERNAL_", "true")
+	t.Setenv("REGISTRATOR_TTL", "10")
+	t.Setenv("REGISTRATOR_TLL_REFRESH", "10")
+	t.Setenv("REGISTRATOR_SOMETHING_ELSE", "1")
+	t.Setenv("OTHER_INTERNAL", "true")
+
+	assert.Equal(t, []*UnknownVar{
+		{Name: "REGISTRATOR_INTERNAL_", Suggestion: "REGISTRATOR_INTERNAL"},
+		{Name: "REGISTRATOR_SOMETHING_ELSE"},
+		{Name: "REGISTRATOR_TLL_REFRESH", Suggestion: "REGISTRATOR_TTL_REFRESH"},
+	}, set.Unknown())
+	assert.NoError(t, set.Err())
+
+	set.SetStrict(true)
+	assert.EqualError(t, set.Err(), "env: unknown variable REGISTRATOR_INTERNAL_, did you mean REGISTRATOR_INTERNAL?\n"+
+		"env: unknown variable REGISTRATOR_SOMETHING_ELSE\n"+
+		"env: unknown variable REGISTRATOR_TLL_REFRESH, did you mean REGISTRATOR_TTL_REFRESH?")
+}
+
+func TestUnknownNoPrefix(t *testing.T) {
+	set := NewEnvSet("test")
+	set.SetStrict(true)
+	t.Setenv("REGISTRATOR_INTERNAL_", "true")
+	assert.Nil(t, set.Unknown())
+	assert.NoError(t, set.Err())
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("abc", "abc"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+	assert.Equal(t, 1, editDistance("abc", "abd"))
+	assert.Equal(t, 2, editDistance("ttl", "tlt"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
@@ -0,0 +1,191 @@
+package env
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// usageWidth is the width descriptions are wrapped to by PrintDefaults.
+const usageWidth = 80
+
+// usageIndent precedes the description lines printed by PrintDefaults.
+const usageIndent = "        "
+
+// SetOutput sets the destination for usage messages. If out is nil,
+// os.Stderr is used.
+func (e *EnvSet) SetOutput(out io.Writer) {
+	e.Lock()
+	defer e.Unlock()
+	e.out = out
+}
+
+// SetOutput sets the destination for usage messages. If out is nil,
+// os.Stderr is used.
+func SetOutput(out io.Writer) {
+	DefaultEnv.SetOutput(out)
+}
+
+// Output returns the destination for usage messages.
+func (e *EnvSet) Output() io.Writer {
+	e.Lock()
+	defer e.Unlock()
+	if e.out == nil {
+		return os.Stderr
+	}
+	return e.out
+}
+
+// Output returns the destination for usage messages.
+func Output() io.Writer {
+	return DefaultEnv.Output()
+}
+
+// Usage prints the documentation of the variables of DefaultEnv by calling
+// its Usage func.
+func Usage() {
+	DefaultEnv.usage()
+}
+
+// usage calls the Usage func of e, or defaultUsage if it is nil, as for an
+// EnvSet that was not made by NewEnvSet.
+func (e *EnvSet) usage() {
+	if e.Usage == nil {
+		e.defaultUsage()
+	} else {
+		e.Usage()
+	}
+}
+
+// defaultUsage is the default Usage func.
+func (e *EnvSet) defaultUsage() {
+	e.printUsage(e.Output())
+}
+
+// printUsage prints the ENVIRONMENT section to out.
+func (e *EnvSet) printUsage(out io.Writer) {
+	fmt.Fprintln(out, "\nENVIRONMENT")
+	e.PrintDefaults(out)
+}
+
+// AppendUsage makes the usage message of fs, e.g. flag.CommandLine, end with
+// the ENVIRONMENT section of e, so that -h documents the variables too. It
+// wraps the Usage func of fs, which must not be replaced afterwards; for
+// flag.CommandLine, flag.Usage may still be.
+func (e *EnvSet) AppendUsage(fs *flag.FlagSet) {
+	usage := fs.Usage
+	fs.Usage = func() {
+		if usage != nil {
+			usage()
+		} else {
+			if fs.Name() == "" {
+				fmt.Fprintf(fs.Output(), "Usage:\n")
+			} else {
+				fmt.Fprintf(fs.Output(), "Usage of %s:\n", fs.Name())
+			}
+			fs.PrintDefaults()
+		}
+		e.printUsage(fs.Output())
+	}
+}
+
+// AppendUsage makes the usage message of flag.CommandLine end with the
+// ENVIRONMENT section of DefaultEnv.
+func AppendUsage() {
+	DefaultEnv.AppendUsage(flag.CommandLine)
+}
+
+// printDefault prints the usage of v in the manner of flag.PrintDefaults:
+// name, type and markers on one line, then the wrapped description, options
+// and default.
+func printDefault(out io.Writer, v *ConfigVar) {
+	var b strings.Builder
+	typ := v.Type()
+	fmt.Fprintf(&b, "  %s %s", v.Name, typ)
+	var markers []string
+	if v.Required {
+		markers = append(markers, "required")
+	}
+	if v.Secret {
+		markers = append(markers, "secret")
+	}
+	if len(markers) > 0 {
+		fmt.Fprintf(&b, " [%s]", strings.Join(markers, ", "))
+	}
+	b.WriteString("\n")
+
+	desc := v.usage()
+	if len(v.Options) > 0 {
+		desc = strings.TrimSpace(desc + " (one of: " + strings.Join(v.Options, ", ") + ")")
+	}
+	if !v.Secret && !isZeroValue(v) {
+		def := v.Default
+		if typ == "string" {
+			def = fmt.Sprintf("%q", def)
+		}
+		desc = strings.TrimSpace(desc + " (default " + def + ")")
+	}
+	for _, line := range wrap(desc, usageWidth-len(usageIndent)) {
+		b.WriteString(usageIndent + line + "\n")
+	}
+	io.WriteString(out, b.String())
+}
+
+// isZeroValue reports whether v's default is the zero value of its type, as
+// flag does to omit it.
+func isZeroValue(v *ConfigVar) (zero bool) {
+	if v.Default == "" {
+		return true
+	}
+	if z, ok := v.Value.(interface{ zeroString() string }); ok {
+		return v.Default == z.zeroString()
+	}
+	typ := reflect.TypeOf(v.Value)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return false
+	}
+	// The built-in Values format their zero value, but the String of a
+	// custom Value may panic on it, which is then treated as non-zero.
+	defer func() {
+		if recover() != nil {
+			zero = false
+		}
+	}()
+	return v.Default == reflect.New(typ.Elem()).Interface().(Value).String()
+}
+
+// wrap splits s into lines of at most width bytes, breaking at spaces.
+// Existing line breaks are kept and words longer than width are not split.
+func wrap(s string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			switch {
+			case line == "":
+				line = word
+			case len(line)+1+len(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// optionText returns options formatted as text.
+func optionText[T any](options []T) []string {
+	text := make([]string, len(options))
+	for i, o := range options {
+		text[i] = fmt.Sprint(o)
+	}
+	return text
+}
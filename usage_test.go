@@ -0,0 +1,110 @@
+package env
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintDefaultsLayout(t *testing.T) {
+	set := NewEnvSet("test")
+	set.Require("app_token")
+	set.Int("app_port", 8080, "Listen port")
+	set.StringOption("app_mode", "fast", []string{"fast", "safe"}, "Processing mode")
+	set.Duration("app_timeout", 0, strings.Repeat("Request timeout. ", 6))
+	set.Bool("app_debug", false, "")
+	set.Secret("app_token", "API token")
+
+	var buf bytes.Buffer
+	set.PrintDefaults(&buf)
+	assert.Equal(t, `  APP_DEBUG bool
+  APP_MODE string
+        Processing mode (one of: fast, safe) (default "fast")
+  APP_PORT int
+        Listen port (default 8080)
+  APP_TIMEOUT duration
+        Request timeout. Request timeout. Request timeout. Request timeout.
+        Request timeout. Request timeout.
+  APP_TOKEN string [required, secret]
+        API token
+`, buf.String())
+}
+
+func TestUsage(t *testing.T) {
+	set := NewEnvSet("test")
+	set.Duration("app_timeout", time.Second, "Request timeout")
+
+	var buf bytes.Buffer
+	set.SetOutput(&buf)
+	set.Usage()
+	assert.Equal(t, "\nENVIRONMENT\n  APP_TIMEOUT duration\n        Request timeout (default 1s)\n", buf.String())
+
+	called := false
+	set.Usage = func() { called = true }
+	set.Usage()
+	assert.True(t, called)
+}
+
+func TestAppendUsage(t *testing.T) {
+	set := NewEnvSet("test")
+	set.Int("app_port", 8080, "Listen port")
+
+	var buf bytes.Buffer
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+	fs.Bool("v", false, "verbose")
+	set.AppendUsage(fs)
+
+	assert.ErrorIs(t, fs.Parse([]string{"-h"}), flag.ErrHelp)
+	assert.Equal(t, "Usage of app:\n  -v\tverbose\n\nENVIRONMENT\n  APP_PORT int\n        Listen port (default 8080)\n", buf.String())
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"a b", "c", "dddddd", "e"}, wrap("a b c dddddd\ne", 4))
+	assert.Nil(t, wrap("", 10))
+}
+
+type customValue struct{ stringValue }
+
+func (c *customValue) Type() string { return "custom" }
+
+// untypedValue does not implement Typer.
+type untypedValue struct{}
+
+func (untypedValue) String() string   { return "" }
+func (untypedValue) Set(string) error { return nil }
+func (untypedValue) Get() interface{} { return uint16(0) }
+
+func TestConfigVarType(t *testing.T) {
+	set := NewEnvSet("test")
+	assert.Equal(t, "string", set.NewVar(newStringValue(""), "a", "").Type())
+	assert.Equal(t, "list", set.NewVar(newStringListValue(nil), "b", "").Type())
+	assert.Equal(t, "duration", set.NewVar(newDurationValue(0), "c", "").Type())
+	assert.Equal(t, "ip", set.NewVar(newIPValue(nil), "d", "").Type())
+	assert.Equal(t, "custom", set.NewVar(&customValue{}, "e", "").Type())
+	assert.Equal(t, "uint16", set.NewVar(untypedValue{}, "f", "").Type())
+}
+
+func TestPrintDefaultsZeroOption(t *testing.T) {
+	set := NewEnvSet("test")
+	set.IntOption("app_level", 0, []int{0, 1}, "Level")
+	set.IntOption("app_workers", 2, []int{2, 4}, "Workers")
+
+	var buf bytes.Buffer
+	set.PrintDefaults(&buf)
+	assert.Equal(t, "  APP_LEVEL int\n        Level (one of: 0, 1)\n  APP_WORKERS int\n        Workers (one of: 2, 4) (default 2)\n", buf.String())
+}
+
+func TestUsageZeroEnvSet(t *testing.T) {
+	set := &EnvSet{}
+	set.Int("app_port", 8080, "Listen port")
+
+	var buf bytes.Buffer
+	set.SetOutput(&buf)
+	set.usage()
+	assert.Equal(t, "\nENVIRONMENT\n  APP_PORT int\n        Listen port (default 8080)\n", buf.String())
+}
@@ -3,9 +3,15 @@ package env
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,6 +21,53 @@ type Value interface {
 	Get() interface{}
 }
 
+// Typer is implemented by Values that name their type, e.g. "duration", for
+// PrintDefaults and other documentation. All built-in Values implement it.
+type Typer interface {
+	Type() string
+}
+
+// -- option Value
+// optionValue restricts the values of a Value to options.
+type optionValue[T comparable] struct {
+	Value
+	options []T
+	typ     string // Type of the wrapped Value
+	zero    string // text of the zero value of the wrapped Value
+}
+
+// newOptionValue wraps value, which must be a pointer to a built-in Value.
+// Its type and zero text are recorded here because Set replaces it.
+func newOptionValue[T comparable](value Value, options []T) *optionValue[T] {
+	o := &optionValue[T]{Value: value, options: options, typ: "value"}
+	if t, ok := value.(Typer); ok {
+		o.typ = t.Type()
+	}
+	o.zero = reflect.New(reflect.TypeOf(value).Elem()).Interface().(Value).String()
+	return o
+}
+
+// Set parses s into a copy of the Value, which replaces it only if valid, so
+// the default saved by saveValue is kept.
+func (o *optionValue[T]) Set(s string) error {
+	v := copyValue(o.Value)
+	if err := v.Set(s); err != nil {
+		return err
+	}
+	val, _ := v.Get().(T)
+	for _, option := range o.options {
+		if option == val {
+			o.Value = v
+			return nil
+		}
+	}
+	return fmt.Errorf("%v is not one of %v", v.Get(), o.options)
+}
+
+func (o *optionValue[T]) Type() string { return o.typ }
+
+func (o *optionValue[T]) zeroString() string { return o.zero }
+
 // -- string Value
 type stringValue string
 
@@ -26,6 +79,8 @@ func (s *stringValue) Set(val string) error {
 	return nil
 }
 
+func (s *stringValue) Type() string { return "string" }
+
 func (s *stringValue) Get() interface{} { return string(*s) }
 
 func (s *stringValue) String() string { return fmt.Sprintf("%s", *s) }
@@ -36,14 +91,179 @@ func newStringListValue(val []string) *stringListValue {
 	return (*stringListValue)(&val)
 }
 func (s *stringListValue) Set(val string) error {
-	*s = stringListValue(strings.Split(",", val))
+	*s = stringListValue(strings.Split(val, ","))
 	return nil
 }
 
+func (s *stringListValue) Type() string { return "list" }
+
 func (s *stringListValue) Get() interface{} { return []string(*s) }
 
 func (s *stringListValue) String() string { return fmt.Sprintf("%s", *s) }
 
+// -- map Values
+const (
+	defaultPairSep = ","
+	defaultKVSep   = ":"
+)
+
+// parseMap splits s into key/value pairs and calls fn for each of them.
+// Pairs missing kvSep, empty keys and duplicate keys are errors.
+func parseMap(s, pairSep, kvSep string, fn func(key, val string) error) error {
+	seen := make(map[string]bool)
+	for _, pair := range strings.Split(s, pairSep) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, kvSep, 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("missing %q in pair %q", kvSep, pair)
+		}
+		key, val := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if key == "" {
+			return fmt.Errorf("empty key in pair %q", pair)
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate key %q", key)
+		}
+		seen[key] = true
+		if err := fn(key, val); err != nil {
+			return fmt.Errorf("key %q: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// formatMap renders pairs sorted by key so the output is stable.
+func formatMap(keys []string, pairSep, kvSep string, val func(key string) string) string {
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + kvSep + val(k)
+	}
+	return strings.Join(pairs, pairSep)
+}
+
+type stringMapValue struct {
+	m              map[string]string
+	pairSep, kvSep string
+}
+
+func newStringMapValue(val map[string]string, pairSep, kvSep string) *stringMapValue {
+	m := make(map[string]string, len(val))
+	for k, v := range val {
+		m[k] = v
+	}
+	return &stringMapValue{m: m, pairSep: pairSep, kvSep: kvSep}
+}
+
+func (s *stringMapValue) Set(val string) error {
+	m := make(map[string]string)
+	err := parseMap(val, s.pairSep, s.kvSep, func(k, v string) error {
+		m[k] = v
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.m = m
+	return nil
+}
+
+func (s *stringMapValue) Type() string { return "map" }
+
+// Get returns a copy, so callers cannot change the value or its saved default.
+func (s *stringMapValue) Get() interface{} { return maps.Clone(s.m) }
+
+func (s *stringMapValue) String() string {
+	keys := make([]string, 0, len(s.m))
+	for k := range s.m {
+		keys = append(keys, k)
+	}
+	return formatMap(keys, s.pairSep, s.kvSep, func(k string) string { return s.m[k] })
+}
+
+type intMapValue struct {
+	m              map[string]int
+	pairSep, kvSep string
+}
+
+func newIntMapValue(val map[string]int, pairSep, kvSep string) *intMapValue {
+	m := make(map[string]int, len(val))
+	for k, v := range val {
+		m[k] = v
+	}
+	return &intMapValue{m: m, pairSep: pairSep, kvSep: kvSep}
+}
+
+func (i *intMapValue) Set(val string) error {
+	m := make(map[string]int)
+	err := parseMap(val, i.pairSep, i.kvSep, func(k, v string) error {
+		n, err := strconv.ParseInt(v, 0, strconv.IntSize)
+		m[k] = int(n)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	i.m = m
+	return nil
+}
+
+func (i *intMapValue) Type() string { return "intmap" }
+
+// Get returns a copy, as for stringMapValue.
+func (i *intMapValue) Get() interface{} { return maps.Clone(i.m) }
+
+func (i *intMapValue) String() string {
+	keys := make([]string, 0, len(i.m))
+	for k := range i.m {
+		keys = append(keys, k)
+	}
+	return formatMap(keys, i.pairSep, i.kvSep, func(k string) string { return strconv.Itoa(i.m[k]) })
+}
+
+type float64MapValue struct {
+	m              map[string]float64
+	pairSep, kvSep string
+}
+
+func newFloat64MapValue(val map[string]float64, pairSep, kvSep string) *float64MapValue {
+	m := make(map[string]float64, len(val))
+	for k, v := range val {
+		m[k] = v
+	}
+	return &float64MapValue{m: m, pairSep: pairSep, kvSep: kvSep}
+}
+
+func (f *float64MapValue) Set(val string) error {
+	m := make(map[string]float64)
+	err := parseMap(val, f.pairSep, f.kvSep, func(k, v string) error {
+		n, err := strconv.ParseFloat(v, 64)
+		m[k] = n
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	f.m = m
+	return nil
+}
+
+func (f *float64MapValue) Type() string { return "float64map" }
+
+// Get returns a copy, as for stringMapValue.
+func (f *float64MapValue) Get() interface{} { return maps.Clone(f.m) }
+
+func (f *float64MapValue) String() string {
+	keys := make([]string, 0, len(f.m))
+	for k := range f.m {
+		keys = append(keys, k)
+	}
+	return formatMap(keys, f.pairSep, f.kvSep, func(k string) string { return fmt.Sprintf("%v", f.m[k]) })
+}
+
 // -- secret Value
 type secretValue stringValue
 
@@ -55,6 +275,8 @@ func (s *secretValue) Set(val string) error {
 	return nil
 }
 
+func (s *secretValue) Type() string { return "string" }
+
 func (s *secretValue) Get() interface{} { return string(*s) }
 
 func (s *secretValue) String() string {
@@ -77,21 +299,57 @@ func (s *secretValue) String() string {
 }
 
 // -- bool Value
-type boolValue bool
+type boolValue struct {
+	b     bool
+	words map[string]bool
+}
+
+// defaultBoolWords are the words accepted by Bool unless the EnvSet is
+// configured with BoolWords. They are matched case-insensitively.
+var defaultBoolWords = boolWords(
+	[]string{"1", "t", "true", "y", "yes", "on", "enable", "enabled"},
+	[]string{"0", "f", "false", "n", "no", "off", "disable", "disabled"},
+)
+
+// ParseBool parses a boolean from the words accepted by Bool by default, such
+// as true/false, yes/no, on/off or enabled/disabled.
+func ParseBool(s string) (bool, error) {
+	b := newBoolValue(false, defaultBoolWords)
+	if err := b.Set(s); err != nil {
+		return false, err
+	}
+	return b.b, nil
+}
 
-func newBoolValue(val bool) *boolValue {
-	return (*boolValue)(&val)
+func boolWords(trueWords, falseWords []string) map[string]bool {
+	words := make(map[string]bool, len(trueWords)+len(falseWords))
+	for _, w := range trueWords {
+		words[strings.ToLower(w)] = true
+	}
+	for _, w := range falseWords {
+		words[strings.ToLower(w)] = false
+	}
+	return words
+}
+
+func newBoolValue(val bool, words map[string]bool) *boolValue {
+	return &boolValue{b: val, words: words}
 }
 
 func (b *boolValue) Set(s string) error {
-	v, err := strconv.ParseBool(s)
-	*b = boolValue(v)
-	return err
+	v, ok := b.words[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return fmt.Errorf("unknown boolean %q", s)
+	}
+	b.b = v
+	return nil
 }
 
-func (b *boolValue) Get() interface{} { return bool(*b) }
+func (b *boolValue) Type() string { return "bool" }
+
+func (b *boolValue) Get() interface{} { return b.b }
 
-func (b *boolValue) String() string { return fmt.Sprintf("%v", *b) }
+func (b *boolValue) String() string { return strconv.FormatBool(b.b) }
 
 func (b *boolValue) IsBoolFlag() bool { return true }
 
@@ -103,15 +361,86 @@ func newIntValue(val int) *intValue {
 }
 
 func (i *intValue) Set(s string) error {
-	v, err := strconv.ParseInt(s, 0, 64)
+	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	if err != nil {
+		return err
+	}
 	*i = intValue(v)
-	return err
+	return nil
 }
 
+func (i *intValue) Type() string { return "int" }
+
 func (i *intValue) Get() interface{} { return int(*i) }
 
 func (i *intValue) String() string { return fmt.Sprintf("%v", *i) }
 
+// -- int8 Value
+type int8Value int8
+
+func newInt8Value(val int8) *int8Value {
+	return (*int8Value)(&val)
+}
+
+func (i *int8Value) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, 8)
+	if err != nil {
+		return err
+	}
+	*i = int8Value(v)
+	return nil
+}
+
+func (i *int8Value) Type() string { return "int8" }
+
+func (i *int8Value) Get() interface{} { return int8(*i) }
+
+func (i *int8Value) String() string { return fmt.Sprintf("%v", *i) }
+
+// -- int16 Value
+type int16Value int16
+
+func newInt16Value(val int16) *int16Value {
+	return (*int16Value)(&val)
+}
+
+func (i *int16Value) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, 16)
+	if err != nil {
+		return err
+	}
+	*i = int16Value(v)
+	return nil
+}
+
+func (i *int16Value) Type() string { return "int16" }
+
+func (i *int16Value) Get() interface{} { return int16(*i) }
+
+func (i *int16Value) String() string { return fmt.Sprintf("%v", *i) }
+
+// -- int32 Value
+type int32Value int32
+
+func newInt32Value(val int32) *int32Value {
+	return (*int32Value)(&val)
+}
+
+func (i *int32Value) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, 32)
+	if err != nil {
+		return err
+	}
+	*i = int32Value(v)
+	return nil
+}
+
+func (i *int32Value) Type() string { return "int32" }
+
+func (i *int32Value) Get() interface{} { return int32(*i) }
+
+func (i *int32Value) String() string { return fmt.Sprintf("%v", *i) }
+
 // -- int64 Value
 type int64Value int64
 
@@ -121,10 +450,15 @@ func newInt64Value(val int64) *int64Value {
 
 func (i *int64Value) Set(s string) error {
 	v, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return err
+	}
 	*i = int64Value(v)
-	return err
+	return nil
 }
 
+func (i *int64Value) Type() string { return "int64" }
+
 func (i *int64Value) Get() interface{} { return int64(*i) }
 
 func (i *int64Value) String() string { return fmt.Sprintf("%v", *i) }
@@ -137,15 +471,86 @@ func newUintValue(val uint) *uintValue {
 }
 
 func (i *uintValue) Set(s string) error {
-	v, err := strconv.ParseUint(s, 0, 64)
+	v, err := strconv.ParseUint(s, 0, strconv.IntSize)
+	if err != nil {
+		return err
+	}
 	*i = uintValue(v)
-	return err
+	return nil
 }
 
+func (i *uintValue) Type() string { return "uint" }
+
 func (i *uintValue) Get() interface{} { return uint(*i) }
 
 func (i *uintValue) String() string { return fmt.Sprintf("%v", *i) }
 
+// -- uint8 Value
+type uint8Value uint8
+
+func newUint8Value(val uint8) *uint8Value {
+	return (*uint8Value)(&val)
+}
+
+func (i *uint8Value) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 8)
+	if err != nil {
+		return err
+	}
+	*i = uint8Value(v)
+	return nil
+}
+
+func (i *uint8Value) Type() string { return "uint8" }
+
+func (i *uint8Value) Get() interface{} { return uint8(*i) }
+
+func (i *uint8Value) String() string { return fmt.Sprintf("%v", *i) }
+
+// -- uint16 Value
+type uint16Value uint16
+
+func newUint16Value(val uint16) *uint16Value {
+	return (*uint16Value)(&val)
+}
+
+func (i *uint16Value) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 16)
+	if err != nil {
+		return err
+	}
+	*i = uint16Value(v)
+	return nil
+}
+
+func (i *uint16Value) Type() string { return "uint16" }
+
+func (i *uint16Value) Get() interface{} { return uint16(*i) }
+
+func (i *uint16Value) String() string { return fmt.Sprintf("%v", *i) }
+
+// -- uint32 Value
+type uint32Value uint32
+
+func newUint32Value(val uint32) *uint32Value {
+	return (*uint32Value)(&val)
+}
+
+func (i *uint32Value) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 32)
+	if err != nil {
+		return err
+	}
+	*i = uint32Value(v)
+	return nil
+}
+
+func (i *uint32Value) Type() string { return "uint32" }
+
+func (i *uint32Value) Get() interface{} { return uint32(*i) }
+
+func (i *uint32Value) String() string { return fmt.Sprintf("%v", *i) }
+
 // -- uint64 Value
 type uint64Value uint64
 
@@ -155,14 +560,41 @@ func newUint64Value(val uint64) *uint64Value {
 
 func (i *uint64Value) Set(s string) error {
 	v, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return err
+	}
 	*i = uint64Value(v)
-	return err
+	return nil
 }
 
+func (i *uint64Value) Type() string { return "uint64" }
+
 func (i *uint64Value) Get() interface{} { return uint64(*i) }
 
 func (i *uint64Value) String() string { return fmt.Sprintf("%v", *i) }
 
+// -- float32 Value
+type float32Value float32
+
+func newFloat32Value(val float32) *float32Value {
+	return (*float32Value)(&val)
+}
+
+func (f *float32Value) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		return err
+	}
+	*f = float32Value(v)
+	return nil
+}
+
+func (f *float32Value) Type() string { return "float32" }
+
+func (f *float32Value) Get() interface{} { return float32(*f) }
+
+func (f *float32Value) String() string { return fmt.Sprintf("%v", *f) }
+
 // -- float64 Value
 type float64Value float64
 
@@ -172,10 +604,15 @@ func newFloat64Value(val float64) *float64Value {
 
 func (f *float64Value) Set(s string) error {
 	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
 	*f = float64Value(v)
-	return err
+	return nil
 }
 
+func (f *float64Value) Type() string { return "float64" }
+
 func (f *float64Value) Get() interface{} { return float64(*f) }
 
 func (f *float64Value) String() string { return fmt.Sprintf("%v", *f) }
@@ -189,15 +626,214 @@ func newDurationValue(val time.Duration) *durationValue {
 
 func (d *durationValue) Set(s string) error {
 	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
 	*d = durationValue(v)
-	return err
+	return nil
 }
 
+func (d *durationValue) Type() string { return "duration" }
+
 func (d *durationValue) Get() interface{} { return time.Duration(*d) }
 
 func (d *durationValue) String() string { return (*time.Duration)(d).String() }
 
-// -- float64 Value
+// -- time.Time Value
+type timeValue struct {
+	t       time.Time
+	layouts []string
+}
+
+func newTimeValue(val time.Time, layouts []string) *timeValue {
+	return &timeValue{t: val, layouts: layouts}
+}
+
+func (t *timeValue) Set(s string) error {
+	var err error
+	for _, layout := range t.layouts {
+		var v time.Time
+		if v, err = time.Parse(layout, s); err == nil {
+			t.t = v
+			return nil
+		}
+	}
+	if len(t.layouts) > 1 {
+		return fmt.Errorf("time %q does not match any of the layouts %q", s, t.layouts)
+	}
+	return err
+}
+
+func (t *timeValue) Type() string { return "time" }
+
+func (t *timeValue) Get() interface{} { return t.t }
+
+func (t *timeValue) String() string {
+	if t.t.IsZero() {
+		return ""
+	}
+	return t.t.Format(t.layouts[0])
+}
+
+// -- *time.Location Value
+type locationValue struct{ loc *time.Location }
+
+func newLocationValue(val *time.Location) *locationValue {
+	return &locationValue{val}
+}
+
+func (l *locationValue) Set(s string) error {
+	v, err := time.LoadLocation(s)
+	if err != nil {
+		return err
+	}
+	l.loc = v
+	return nil
+}
+
+func (l *locationValue) Type() string { return "location" }
+
+func (l *locationValue) Get() interface{} { return l.loc }
+
+func (l *locationValue) String() string {
+	if l.loc == nil {
+		return ""
+	}
+	return l.loc.String()
+}
+
+// -- *Schedule Value
+type scheduleValue struct{ s *Schedule }
+
+func newScheduleValue(val *Schedule) *scheduleValue {
+	return &scheduleValue{val}
+}
+
+func (s *scheduleValue) Set(val string) error {
+	v, err := ParseSchedule(val)
+	if err != nil {
+		return err
+	}
+	s.s = v
+	return nil
+}
+
+func (s *scheduleValue) Type() string { return "cron" }
+
+func (s *scheduleValue) Get() interface{} { return s.s }
+
+func (s *scheduleValue) String() string { return s.s.String() }
+
+// -- ByteSize Value
+type byteSizeValue struct {
+	b        ByteSize
+	min, max ByteSize
+}
+
+func newByteSizeValue(val, min, max ByteSize) *byteSizeValue {
+	return &byteSizeValue{b: val, min: min, max: max}
+}
+
+func (b *byteSizeValue) Set(s string) error {
+	v, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	if v < b.min || v > b.max {
+		return fmt.Errorf("%s is not between %s and %s", v, b.min, b.max)
+	}
+	b.b = v
+	return nil
+}
+
+func (b *byteSizeValue) Type() string { return "size" }
+
+func (b *byteSizeValue) Get() interface{} { return b.b }
+
+func (b *byteSizeValue) String() string { return b.b.String() }
+
+// -- Percent Value
+type percentValue struct {
+	p        Percent
+	min, max Percent
+}
+
+func newPercentValue(val, min, max Percent) *percentValue {
+	return &percentValue{p: val, min: min, max: max}
+}
+
+func (p *percentValue) Set(s string) error {
+	v, err := ParsePercent(s)
+	if err != nil {
+		return err
+	}
+	if v < p.min || v > p.max {
+		return fmt.Errorf("%s is not between %s and %s", v, p.min, p.max)
+	}
+	p.p = v
+	return nil
+}
+
+func (p *percentValue) Type() string { return "percent" }
+
+func (p *percentValue) Get() interface{} { return p.p }
+
+func (p *percentValue) String() string { return p.p.String() }
+
+// -- Rollout Value
+type rolloutValue struct {
+	r     Rollout
+	words map[string]bool
+}
+
+func newRolloutValue(val Rollout, words map[string]bool) *rolloutValue {
+	return &rolloutValue{r: val, words: words}
+}
+
+func (r *rolloutValue) Set(s string) error {
+	v, err := parseRollout(s, r.words)
+	if err != nil {
+		return err
+	}
+	r.r = v
+	return nil
+}
+
+func (r *rolloutValue) Type() string { return "feature" }
+
+func (r *rolloutValue) Get() interface{} { return r.r }
+
+func (r *rolloutValue) String() string { return r.r.String() }
+
+// -- Rate Value
+type rateValue struct {
+	r        Rate
+	min, max Rate
+}
+
+func newRateValue(val, min, max Rate) *rateValue {
+	return &rateValue{r: val, min: min, max: max}
+}
+
+func (r *rateValue) Set(s string) error {
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	if v.PerSecond() < r.min.PerSecond() || (r.max.Per > 0 && v.PerSecond() > r.max.PerSecond()) {
+		return fmt.Errorf("%s is not between %s and %s", v, r.min, r.max)
+	}
+	r.r = v
+	return nil
+}
+
+func (r *rateValue) Type() string { return "rate" }
+
+func (r *rateValue) Get() interface{} { return r.r }
+
+func (r *rateValue) String() string { return r.r.String() }
+
+// -- net.IP Value
 type ipValue net.IP
 
 func newIPValue(val net.IP) *ipValue {
@@ -206,13 +842,253 @@ func newIPValue(val net.IP) *ipValue {
 
 func (f *ipValue) Set(s string) error {
 	v := net.ParseIP(s)
-	*f = ipValue(v)
-	if v != nil {
+	if v == nil {
 		return errors.New("invalid IP")
 	}
+	*f = ipValue(v)
 	return nil
 }
 
+func (f *ipValue) Type() string { return "ip" }
+
 func (f *ipValue) Get() interface{} { return net.IP(*f) }
 
 func (f *ipValue) String() string { return fmt.Sprintf("%v", *f) }
+
+// -- *net.IPNet Value
+type ipNetValue struct{ n *net.IPNet }
+
+func newIPNetValue(val *net.IPNet) *ipNetValue {
+	return &ipNetValue{val}
+}
+
+func (n *ipNetValue) Set(s string) error {
+	_, v, err := net.ParseCIDR(s)
+	if err != nil {
+		return err
+	}
+	n.n = v
+	return nil
+}
+
+func (n *ipNetValue) Type() string { return "cidr" }
+
+func (n *ipNetValue) Get() interface{} { return n.n }
+
+func (n *ipNetValue) String() string {
+	if n.n == nil {
+		return ""
+	}
+	return n.n.String()
+}
+
+// -- netip.Addr Value
+type ipAddrValue netip.Addr
+
+func newIPAddrValue(val netip.Addr) *ipAddrValue {
+	return (*ipAddrValue)(&val)
+}
+
+func (a *ipAddrValue) Set(s string) error {
+	v, err := netip.ParseAddr(s)
+	if err != nil {
+		return err
+	}
+	*a = ipAddrValue(v)
+	return nil
+}
+
+func (a *ipAddrValue) Type() string { return "ip" }
+
+func (a *ipAddrValue) Get() interface{} { return netip.Addr(*a) }
+
+func (a *ipAddrValue) String() string {
+	if !netip.Addr(*a).IsValid() {
+		return ""
+	}
+	return netip.Addr(*a).String()
+}
+
+// -- netip.Prefix Value
+type ipPrefixValue netip.Prefix
+
+func newIPPrefixValue(val netip.Prefix) *ipPrefixValue {
+	return (*ipPrefixValue)(&val)
+}
+
+func (p *ipPrefixValue) Set(s string) error {
+	v, err := netip.ParsePrefix(s)
+	if err != nil {
+		return err
+	}
+	*p = ipPrefixValue(v)
+	return nil
+}
+
+func (p *ipPrefixValue) Type() string { return "cidr" }
+
+func (p *ipPrefixValue) Get() interface{} { return netip.Prefix(*p) }
+
+func (p *ipPrefixValue) String() string {
+	if !netip.Prefix(*p).IsValid() {
+		return ""
+	}
+	return netip.Prefix(*p).String()
+}
+
+// splitHostPort is like net.SplitHostPort except defaultPort is used when s
+// has no port. The port must be numeric.
+func splitHostPort(s, defaultPort string) (host, port string, err error) {
+	host, port, err = net.SplitHostPort(s)
+	if err != nil {
+		host, port = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"), defaultPort
+		if strings.Contains(host, ":") && net.ParseIP(host) == nil {
+			return "", "", err
+		}
+	}
+	if port == "" {
+		return "", "", fmt.Errorf("missing port in address %q", s)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", "", fmt.Errorf("invalid port %q", port)
+	}
+	return host, port, nil
+}
+
+// -- host:port Value
+type hostPortValue struct {
+	addr        string
+	defaultPort string
+}
+
+func newHostPortValue(val string, defaultPort string) *hostPortValue {
+	return &hostPortValue{addr: val, defaultPort: defaultPort}
+}
+
+func (h *hostPortValue) Set(s string) error {
+	host, port, err := splitHostPort(s, h.defaultPort)
+	if err != nil {
+		return err
+	}
+	h.addr = net.JoinHostPort(host, port)
+	return nil
+}
+
+func (h *hostPortValue) Type() string { return "hostport" }
+
+func (h *hostPortValue) Get() interface{} { return h.addr }
+
+func (h *hostPortValue) String() string { return h.addr }
+
+// -- *net.TCPAddr Value
+// The address is only resolved the first time it is used.
+type tcpAddrValue struct {
+	addr    string
+	resolve func() (*net.TCPAddr, error)
+}
+
+func newTCPAddrValue(val string) *tcpAddrValue {
+	t := &tcpAddrValue{}
+	t.setAddr(val)
+	return t
+}
+
+func (t *tcpAddrValue) setAddr(addr string) {
+	t.addr = addr
+	t.resolve = sync.OnceValues(func() (*net.TCPAddr, error) {
+		return net.ResolveTCPAddr("tcp", addr)
+	})
+}
+
+func (t *tcpAddrValue) Set(s string) error {
+	if _, _, err := splitHostPort(s, ""); err != nil {
+		return err
+	}
+	t.setAddr(s)
+	return nil
+}
+
+func (t *tcpAddrValue) Type() string { return "tcpaddr" }
+
+func (t *tcpAddrValue) Get() interface{} { return t.resolve }
+
+func (t *tcpAddrValue) String() string { return t.addr }
+
+// -- *net.UDPAddr Value
+// The address is only resolved the first time it is used.
+type udpAddrValue struct {
+	addr    string
+	resolve func() (*net.UDPAddr, error)
+}
+
+func newUDPAddrValue(val string) *udpAddrValue {
+	u := &udpAddrValue{}
+	u.setAddr(val)
+	return u
+}
+
+func (u *udpAddrValue) setAddr(addr string) {
+	u.addr = addr
+	u.resolve = sync.OnceValues(func() (*net.UDPAddr, error) {
+		return net.ResolveUDPAddr("udp", addr)
+	})
+}
+
+func (u *udpAddrValue) Set(s string) error {
+	if _, _, err := splitHostPort(s, ""); err != nil {
+		return err
+	}
+	u.setAddr(s)
+	return nil
+}
+
+func (u *udpAddrValue) Type() string { return "udpaddr" }
+
+func (u *udpAddrValue) Get() interface{} { return u.resolve }
+
+func (u *udpAddrValue) String() string { return u.addr }
+
+// -- *url.URL Value
+type urlValue struct {
+	u       *url.URL
+	schemes []string
+}
+
+func newURLValue(val *url.URL, schemes []string) *urlValue {
+	return &urlValue{u: val, schemes: schemes}
+}
+
+func (u *urlValue) Set(s string) error {
+	v, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if !v.IsAbs() {
+		return fmt.Errorf("url %q is not absolute", s)
+	}
+	if len(u.schemes) > 0 {
+		ok := false
+		for _, scheme := range u.schemes {
+			if strings.EqualFold(scheme, v.Scheme) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("scheme %q is not one of %s", v.Scheme, strings.Join(u.schemes, ", "))
+		}
+	}
+	u.u = v
+	return nil
+}
+
+func (u *urlValue) Type() string { return "url" }
+
+func (u *urlValue) Get() interface{} { return u.u }
+
+func (u *urlValue) String() string {
+	if u.u == nil {
+		return ""
+	}
+	return u.u.Redacted()
+}